@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 成就记录文件路径
+const achievementsFilePath = "2048_achievements.json"
+
+// 成就条件中的常量
+const (
+	fast4096Moves   = 2100 // 快速合成4096的步数上限
+	cornerTrackTile = 128  // 最大方块达到此值后开始检查是否离开角落
+	comboMerges     = 4    // 一步之内的合并次数
+)
+
+// 成就提示的显示时长和淡入淡出的帧数
+const (
+	toastFrames     = 180
+	toastFadeFrames = 15
+)
+
+// 成就列表的布局
+const (
+	achievementsListTop   = 100
+	achievementsRowHeight = 44
+)
+
+// achievement 一项成就，满足条件时解锁
+//
+// 名称和说明的消息键为 achievement.<id>.name 和 achievement.<id>.desc。
+type achievement struct {
+	id    string
+	check func(g *Game, e moveEvent) bool
+}
+
+// 所有成就，按显示顺序排列
+var achievements = []achievement{
+	{"first_game", func(g *Game, e moveEvent) bool {
+		return e.gameOver
+	}},
+	{"first_2048", func(g *Game, e moveEvent) bool {
+		return e.maxTile >= 2048
+	}},
+	{"no_undo_2048", func(g *Game, e moveEvent) bool {
+		return e.maxTile >= 2048 && g.record.Undos == 0
+	}},
+	{"fast_4096", func(g *Game, e moveEvent) bool {
+		return e.maxTile >= 4096 && e.moves <= fast4096Moves
+	}},
+	{"corner_win", func(g *Game, e moveEvent) bool {
+		return e.won && !g.record.LeftCorner
+	}},
+	{"combo", func(g *Game, e moveEvent) bool {
+		return len(e.merges) >= comboMerges
+	}},
+	{"score_20000", func(g *Game, e moveEvent) bool {
+		return g.score >= 20000
+	}},
+	{"small_board_512", func(g *Game, e moveEvent) bool {
+		return len(g.board) == minBoardSize && e.maxTile >= 512
+	}},
+}
+
+// 加载已解锁的成就
+func loadAchievements() map[string]time.Time {
+	unlocked := map[string]time.Time{}
+	data, err := ioutil.ReadFile(achievementsFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取成就记录失败: %v", err)
+		}
+		return unlocked
+	}
+	if err := json.Unmarshal(data, &unlocked); err != nil {
+		log.Printf("解析成就记录失败: %v", err)
+		return map[string]time.Time{}
+	}
+	return unlocked
+}
+
+// 保存已解锁的成就
+func saveAchievements(unlocked map[string]time.Time) error {
+	data, err := json.MarshalIndent(unlocked, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(achievementsFilePath, data, 0644)
+}
+
+// 最大方块是否在角落
+func (b Board) maxInCorner() bool {
+	n := len(b) - 1
+	max := b.maxTile()
+	return b[0][0] == max || b[0][n] == max || b[n][0] == max || b[n][n] == max
+}
+
+// 根据移动事件检查成就，练习局和回放不解锁成就
+func (g *Game) checkAchievements(e moveEvent) {
+	if !g.recording || g.record.Practice {
+		return
+	}
+
+	if e.maxTile >= cornerTrackTile && !g.board.maxInCorner() {
+		g.record.LeftCorner = true
+	}
+
+	unlocked := false
+	for _, a := range achievements {
+		if _, ok := g.achievements[a.id]; ok || !a.check(g, e) {
+			continue
+		}
+		g.achievements[a.id] = time.Now()
+		g.toasts = append(g.toasts, tr("achievement."+a.id+".name"))
+		log.Printf("解锁成就: %s", a.id)
+		unlocked = true
+	}
+
+	if unlocked {
+		if err := saveAchievements(g.achievements); err != nil {
+			log.Printf("保存成就记录失败: %v", err)
+		}
+	}
+}
+
+// 更新成就提示，依次显示队列中的提示
+func (g *Game) updateToasts() {
+	if g.toastTime > 0 {
+		g.toastTime--
+		if g.toastTime == 0 {
+			g.toasts = g.toasts[1:]
+		}
+		return
+	}
+	if len(g.toasts) > 0 {
+		g.toastTime = toastFrames
+	}
+}
+
+// 绘制成就提示，位于消息提示的下方
+func (g *Game) drawToast(screen *ebiten.Image) {
+	if g.toastTime == 0 || len(g.toasts) == 0 {
+		return
+	}
+
+	// 开始和结束时淡入淡出
+	alpha := 1.0
+	if elapsed := toastFrames - g.toastTime; elapsed < toastFadeFrames {
+		alpha = float64(elapsed) / toastFadeFrames
+	} else if g.toastTime < toastFadeFrames {
+		alpha = float64(g.toastTime) / toastFadeFrames
+	}
+
+	title := tr("toast.achievement")
+	name := g.toasts[0]
+	titleWidth, _ := textSize(scoreFont, title)
+	nameWidth, _ := textSize(boldFont, name)
+	width := titleWidth
+	if nameWidth > width {
+		width = nameWidth
+	}
+	width += 30
+
+	centerX := float64(layout.boardX) + boardPixels/2
+	top := float64(layout.boardY) + 46
+	gold := currentTheme.tileColor(2048)
+	drawRect(screen, centerX-width/2, top, width, 50, color.NRGBA{0, 0, 0, uint8(200 * alpha)})
+	drawRect(screen, centerX-width/2, top, 4, 50, color.NRGBA{gold.R, gold.G, gold.B, uint8(255 * alpha)})
+	white := color.NRGBA{255, 255, 255, uint8(255 * alpha)}
+	drawTextCentered(screen, title, scoreFont, centerX, top+18, white)
+	drawTextCentered(screen, name, boldFont, centerX, top+40, white)
+}
+
+// 成就列表场景
+type achievementsScene struct{}
+
+// 打开成就列表
+func (g *Game) openAchievements() {
+	g.pushScene(achievementsScene{})
+}
+
+func (achievementsScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		g.popScene()
+	}
+}
+
+func (achievementsScene) draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	drawTextCentered(screen, tr("achievements.title", len(g.achievements), len(achievements)), titleFont, centerX, 60, currentTheme.Text)
+
+	rowHeight := achievementsRowHeight
+	if h := int(layout.canvasHeight-achievementsListTop-30) / len(achievements); h < rowHeight {
+		rowHeight = h
+	}
+	for i, a := range achievements {
+		y := float64(achievementsListTop + i*rowHeight)
+		_, unlocked := g.achievements[a.id]
+
+		// 已解锁的成就使用面板颜色，未解锁的淡化显示
+		bg, col := currentTheme.EmptyTile, currentTheme.Text
+		if unlocked {
+			bg, col = currentTheme.Board, currentTheme.TextLight
+		}
+		drawRect(screen, boardMargin, y, layout.canvasWidth-2*boardMargin, float64(rowHeight-4), bg)
+		drawText(screen, tr("achievement."+a.id+".name"), boldFont, boardMargin+10, y+float64(rowHeight)/2-2, col)
+		drawText(screen, tr("achievement."+a.id+".desc"), scoreFont, boardMargin+10, y+float64(rowHeight)-10, col)
+	}
+
+	drawTextCentered(screen, tr("achievements.help"), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+}
+
+func (achievementsScene) overlay() bool { return false }
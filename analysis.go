@@ -0,0 +1,207 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 分析结果中显示的失误数
+const analysisBlunders = 3
+
+// 分析界面布局
+const (
+	analysisBoardsTop = 150 // 棋盘对比的起始纵坐标
+	analysisBoardGap  = 20
+)
+
+// moveReview 对一步走法的评价
+type moveReview struct {
+	index       int // 第几步，从 1 开始
+	played      int // 实际的方向
+	best        int // 评估最好的方向
+	before      Board
+	playedAfter Board // 实际走法滑动后的棋盘（新方块生成前）
+	bestAfter   Board // 最佳走法滑动后的棋盘
+	loss        float64
+}
+
+// gameAnalysis 一局游戏的赛后分析
+type gameAnalysis struct {
+	record   GameRecord
+	accuracy float64 // 准确率（百分比），只统计有多个可选方向的步
+	blunders []moveReview
+}
+
+// 用评估函数逐步重放一局，给每一步与最佳走法比较打分
+//
+// 每步的得分为实际走法在最差和最佳走法之间的位置，准确率是各步得分的平均值。
+func analyzeRecord(r GameRecord) gameAnalysis {
+	fourChance := findRuleVariant(r.Rules).fourChance
+	board := r.InitialBoard.clone()
+	a := gameAnalysis{record: r, accuracy: 100}
+
+	var reviews []moveReview
+	rated, totalQuality := 0, 0.0
+	for i, m := range r.Moves {
+		if !validDirection(m.Direction) {
+			break
+		}
+		values, ok := evaluateMoves(board, fourChance)
+		after, _, moved := slideBoard(board, m.Direction)
+		if !moved {
+			// 记录与棋盘不一致，后面的步无法分析
+			break
+		}
+
+		best, worst, options := m.Direction, m.Direction, 0
+		for d := range values {
+			if !ok[d] {
+				continue
+			}
+			options++
+			if values[d] > values[best] {
+				best = d
+			}
+			if values[d] < values[worst] {
+				worst = d
+			}
+		}
+
+		if options > 1 {
+			rated++
+			quality := 1.0
+			if spread := values[best] - values[worst]; spread > 0 {
+				quality = (values[m.Direction] - values[worst]) / spread
+			}
+			totalQuality += quality
+
+			if loss := values[best] - values[m.Direction]; loss > 0 {
+				bestAfter, _, _ := slideBoard(board, best)
+				reviews = append(reviews, moveReview{
+					index:       i + 1,
+					played:      m.Direction,
+					best:        best,
+					before:      board,
+					playedAfter: after.clone(),
+					bestAfter:   bestAfter,
+					loss:        loss,
+				})
+			}
+		}
+
+		// 按记录生成新方块，得到下一步之前的棋盘
+		m.placeSpawn(after)
+		board = after
+	}
+
+	if rated > 0 {
+		a.accuracy = totalQuality / float64(rated) * 100
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].loss > reviews[j].loss
+	})
+	if len(reviews) > analysisBlunders {
+		reviews = reviews[:analysisBlunders]
+	}
+	a.blunders = reviews
+	return a
+}
+
+// 赛后分析场景
+type analysisScene struct {
+	analysis gameAnalysis
+	selected int
+}
+
+// 分析一局游戏并打开分析界面
+func (g *Game) openAnalysis(r GameRecord) {
+	g.pushScene(&analysisScene{analysis: analyzeRecord(r)})
+}
+
+func (s *analysisScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || g.keys.justPressed(ActionAnalyze) {
+		g.popScene()
+		return
+	}
+
+	// 切换查看的失误
+	n := len(s.analysis.blunders)
+	if n == 0 {
+		return
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft), inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		s.selected = stepIndex(s.selected, -1, n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight), inpututil.IsKeyJustPressed(ebiten.KeyArrowDown),
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		s.selected = stepIndex(s.selected, 1, n)
+	}
+}
+
+func (s *analysisScene) draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	a := &s.analysis
+	drawTextCentered(screen, tr("analysis.title"), titleFont, centerX, 60, currentTheme.Text)
+	drawTextCentered(screen, tr("analysis.summary", a.accuracy, len(a.record.Moves), a.record.Score), boldFont, centerX, 90, currentTheme.Text)
+	drawTextCentered(screen, tr("analysis.help"), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+
+	if len(a.blunders) == 0 {
+		drawTextCentered(screen, tr("analysis.no_blunders"), boldFont, centerX, layout.canvasHeight/2, currentTheme.Text)
+		return
+	}
+
+	b := a.blunders[s.selected]
+	drawTextCentered(screen, tr("analysis.blunder", s.selected+1, len(a.blunders), b.index, b.loss), boldFont, centerX, 120, currentTheme.Text)
+
+	// 三个棋盘并排：移动前、实际走法、最佳走法
+	size := (layout.canvasWidth - 4*analysisBoardGap) / 3
+	if maxSize := layout.canvasHeight - analysisBoardsTop - 40; size > maxSize {
+		size = maxSize
+	}
+	left := centerX - (3*size+2*analysisBoardGap)/2
+	boards := []struct {
+		label string
+		board Board
+	}{
+		{tr("analysis.before"), b.before},
+		{tr("analysis.played", directionSymbols[b.played]), b.playedAfter},
+		{tr("analysis.best", directionSymbols[b.best]), b.bestAfter},
+	}
+	for i, item := range boards {
+		x := left + float64(i)*(size+analysisBoardGap)
+		drawTextCentered(screen, item.label, scoreFont, x+size/2, analysisBoardsTop-8, currentTheme.Text)
+		drawMiniBoard(screen, item.board, x, analysisBoardsTop, size)
+	}
+}
+
+func (s *analysisScene) overlay() bool { return false }
+
+// 在指定位置绘制缩小的棋盘，方块太小时不显示数字
+func drawMiniBoard(screen *ebiten.Image, b Board, x, y, size float64) {
+	n := float64(len(b))
+	gap := size * 0.03
+	cell := (size - gap*(n+1)) / n
+
+	drawRect(screen, x, y, size, size, currentTheme.Board)
+	for r, row := range b {
+		for c, v := range row {
+			cx := x + gap + float64(c)*(cell+gap)
+			cy := y + gap + float64(r)*(cell+gap)
+			if v == 0 {
+				drawRect(screen, cx, cy, cell, cell, currentTheme.EmptyTile)
+				continue
+			}
+			drawRect(screen, cx, cy, cell, cell, currentTheme.tileColor(v))
+			if cell < 20 {
+				continue
+			}
+			label := tileLabel(v)
+			face := tileFaceFor(label, int(cell))
+			w, h := textSize(face, label)
+			drawText(screen, label, face, cx+(cell-w)/2, cy+(cell+h)/2, currentTheme.tileTextColor(v))
+		}
+	}
+}
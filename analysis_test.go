@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func TestAnalyzeRecord(t *testing.T) {
+	// 向上会让最大方块离开角落，向右最好
+	corner := Board{{0, 0, 0, 0}, {0, 0, 0, 0}, {2, 0, 0, 0}, {256, 64, 16, 4}}
+	onlyDown := Board{{2, 4, 8, 16}, {4, 8, 16, 32}, {8, 16, 32, 64}, {0, 0, 0, 0}}
+
+	tests := []struct {
+		name     string
+		board    Board
+		moves    []MoveRecord
+		accuracy float64
+		blunder  *moveReview // 只比较 index、played 和 best
+	}{
+		{
+			name:     "最佳走法",
+			board:    corner,
+			moves:    []MoveRecord{{Direction: DirectionRight}},
+			accuracy: 100,
+		},
+		{
+			name:     "最差走法",
+			board:    corner,
+			moves:    []MoveRecord{{Direction: DirectionUp}},
+			accuracy: 0,
+			blunder:  &moveReview{index: 1, played: DirectionUp, best: DirectionRight},
+		},
+		{
+			name:     "只有一个方向可走时不计入准确率",
+			board:    onlyDown,
+			moves:    []MoveRecord{{Direction: DirectionDown}},
+			accuracy: 100,
+		},
+		{
+			name:     "方向无效时停止分析",
+			board:    corner,
+			moves:    []MoveRecord{{Direction: 7}, {Direction: DirectionUp}},
+			accuracy: 100,
+		},
+		{
+			name:     "与棋盘不一致的步停止分析",
+			board:    corner,
+			moves:    []MoveRecord{{Direction: DirectionDown}, {Direction: DirectionUp}},
+			accuracy: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyzeRecord(GameRecord{Rules: "classic", InitialBoard: tt.board, Moves: tt.moves})
+			if a.accuracy != tt.accuracy {
+				t.Fatalf("accuracy = %v, want %v", a.accuracy, tt.accuracy)
+			}
+			if tt.blunder == nil {
+				if len(a.blunders) != 0 {
+					t.Fatalf("blunders = %+v, want none", a.blunders)
+				}
+				return
+			}
+			if len(a.blunders) != 1 {
+				t.Fatalf("blunders = %+v, want 1", a.blunders)
+			}
+			b := a.blunders[0]
+			if b.index != tt.blunder.index || b.played != tt.blunder.played || b.best != tt.blunder.best || b.loss <= 0 {
+				t.Fatalf("blunder = %+v, want %+v", b, *tt.blunder)
+			}
+		})
+	}
+}
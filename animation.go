@@ -0,0 +1,120 @@
+package main
+
+import (
+	"time"
+)
+
+// 默认动画时长（毫秒）
+const (
+	defaultSlideDurationMs = 100 // 方块滑动
+	defaultMergeDurationMs = 150 // 合并弹出
+	defaultSpawnDurationMs = 120 // 新方块出现
+)
+
+// 单帧最多推进的动画时间，避免窗口拖动等卡顿后动画直接跳到结尾
+const maxFrameDelta = 100 * time.Millisecond
+
+// 按动画速度缩放时长
+func (g *Game) scaledDuration(ms int) time.Duration {
+	speed := g.config.AnimationSpeed
+	if speed <= 0 {
+		speed = 1
+	}
+	return time.Duration(float64(ms) * float64(time.Millisecond) / speed)
+}
+
+// 滑动阶段时长
+func (g *Game) slideDuration() time.Duration {
+	return g.scaledDuration(g.config.SlideDurationMs)
+}
+
+// 合并弹出阶段时长，没有合并时为 0
+func (g *Game) mergeDuration() time.Duration {
+	for _, anim := range g.animations {
+		if anim.animType == AnimationMerge {
+			return g.scaledDuration(g.config.MergeDurationMs)
+		}
+	}
+	return 0
+}
+
+// 新方块出现阶段时长，没有新方块时为 0
+func (g *Game) spawnDuration() time.Duration {
+	for _, anim := range g.animations {
+		if anim.animType == AnimationSpawn {
+			return g.scaledDuration(g.config.SpawnDurationMs)
+		}
+	}
+	return 0
+}
+
+// 动画总时长：滑动结束后，合并弹出和新方块出现同时播放
+func (g *Game) animationTotal() time.Duration {
+	after := g.mergeDuration()
+	if spawn := g.spawnDuration(); spawn > after {
+		after = spawn
+	}
+	return g.slideDuration() + after
+}
+
+// 计算距上一帧的时间，与 TPS 无关
+func (g *Game) frameDelta() time.Duration {
+	return frameDeltaSince(&g.lastUpdate)
+}
+
+// 计算距 last 的时间并把 last 更新为现在，第一帧为 0，卡顿时不超过 maxFrameDelta
+func frameDeltaSince(last *time.Time) time.Duration {
+	now := time.Now()
+	if last.IsZero() {
+		*last = now
+		return 0
+	}
+
+	dt := now.Sub(*last)
+	*last = now
+	if dt > maxFrameDelta {
+		dt = maxFrameDelta
+	}
+	return dt
+}
+
+// 推进动画，返回动画是否刚刚结束
+func (g *Game) advanceAnimation(dt time.Duration) bool {
+	if !g.animating {
+		return false
+	}
+
+	g.animationElapsed += dt
+	if g.animationElapsed >= g.animationTotal() {
+		g.finishAnimation()
+		return true
+	}
+	return false
+}
+
+// 各阶段的进度 (0.0 - 1.0)：滑动阶段，以及随后的合并弹出和新方块出现阶段
+func (g *Game) animationPhases() (slide, merge, spawn float64) {
+	slide = phaseProgress(g.animationElapsed, 0, g.slideDuration())
+	merge = phaseProgress(g.animationElapsed, g.slideDuration(), g.mergeDuration())
+	spawn = phaseProgress(g.animationElapsed, g.slideDuration(), g.spawnDuration())
+	return slide, merge, spawn
+}
+
+// 计算某一阶段的进度
+func phaseProgress(elapsed, start, duration time.Duration) float64 {
+	if duration <= 0 {
+		if elapsed >= start {
+			return 1
+		}
+		return 0
+	}
+
+	p := float64(elapsed-start) / float64(duration)
+	if p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
+	}
+	return p
+}
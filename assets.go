@@ -0,0 +1,40 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// 内置资源，编译进程序中，从任意目录启动都能使用
+//
+//go:embed asset
+var builtinAssetFS embed.FS
+
+// 用户资源目录，其中的同名文件会覆盖内置资源
+var assetDirFlag = flag.String("assets", "asset", "用户资源目录，其中的同名文件覆盖内置资源")
+
+// 中文字体文件名
+const chineseFontFile = "zzgf_dianhei.otf"
+
+// 读取资源文件：优先使用用户资源目录中的文件，不存在时使用内置资源
+func readAsset(name string) ([]byte, error) {
+	if *assetDirFlag != "" {
+		data, err := os.ReadFile(filepath.Join(*assetDirFlag, name))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			log.Printf("读取用户资源 %s 失败，使用内置资源: %v", name, err)
+		}
+	}
+	return fs.ReadFile(builtinAssetFS, "asset/"+name)
+}
+
+// 读取内置资源文件
+func readBuiltinAsset(name string) ([]byte, error) {
+	return fs.ReadFile(builtinAssetFS, "asset/"+name)
+}
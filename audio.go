@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// 音频采样率
+const audioSampleRate = 44100
+
+// 每次调整音量的步长
+const volumeStep = 0.1
+
+// Sound 游戏音效
+type Sound int
+
+const (
+	SoundSlide Sound = iota
+	SoundMerge
+	SoundSpawn
+	SoundWin
+	SoundGameOver
+)
+
+// 合并音效的最高音阶，更大的方块使用同一音高
+const maxMergePitch = 20
+
+// audioSystem 音效和背景音乐，所有声音都在程序中合成
+type audioSystem struct {
+	context *audio.Context
+	effects map[string][]byte // 已合成的音效，按名称缓存
+	music   *audio.Player
+}
+
+// 创建音频系统并开始播放背景音乐
+func newAudioSystem() *audioSystem {
+	a := &audioSystem{
+		context: audio.NewContext(audioSampleRate),
+		effects: map[string][]byte{},
+	}
+
+	pcm := synthMusic()
+	music, err := a.context.NewPlayer(audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm))))
+	if err != nil {
+		log.Printf("无法创建背景音乐: %v", err)
+		return a
+	}
+	a.music = music
+	return a
+}
+
+// 根据配置更新音乐音量，音量为零或静音时暂停音乐
+func (g *Game) applyVolume() {
+	if g.audio == nil || g.audio.music == nil {
+		return
+	}
+
+	volume := g.musicVolume()
+	g.audio.music.SetVolume(volume)
+	if volume > 0 && !g.audio.music.IsPlaying() {
+		g.audio.music.Play()
+	} else if volume == 0 && g.audio.music.IsPlaying() {
+		g.audio.music.Pause()
+	}
+}
+
+// 音效的实际音量
+func (g *Game) soundVolume() float64 {
+	if g.config.Muted {
+		return 0
+	}
+	return clampVolume(g.config.Volume * g.config.SoundVolume)
+}
+
+// 音乐的实际音量
+func (g *Game) musicVolume() float64 {
+	if g.config.Muted {
+		return 0
+	}
+	return clampVolume(g.config.Volume * g.config.MusicVolume)
+}
+
+// 将音量限制在 0 到 1 之间
+func clampVolume(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// 播放音效，value 为合并后方块的数值，用于决定合并音效的音高
+func (g *Game) playSound(s Sound, value int) {
+	volume := g.soundVolume()
+	if g.audio == nil || volume == 0 {
+		return
+	}
+
+	pcm := g.audio.effect(s, value)
+	player := g.audio.context.NewPlayerFromBytes(pcm)
+	player.SetVolume(volume)
+	player.Play()
+}
+
+// 获取音效数据，首次使用时合成
+func (a *audioSystem) effect(s Sound, value int) []byte {
+	key := fmt.Sprint(s)
+	pitch := 0
+	if s == SoundMerge {
+		pitch = tileExponent(value)
+		if pitch < 1 || pitch > maxMergePitch {
+			pitch = maxMergePitch
+		}
+		key = fmt.Sprintf("%d-%d", s, pitch)
+	}
+
+	if pcm, ok := a.effects[key]; ok {
+		return pcm
+	}
+	pcm := synthEffect(s, pitch)
+	a.effects[key] = pcm
+	return pcm
+}
+
+// 调整总音量并显示提示
+func (g *Game) changeVolume(delta float64) {
+	g.setVolume(g.config.Volume + delta)
+	g.showMessage(tr("msg.volume", int(math.Round(g.config.Volume*100))), 60)
+}
+
+// 设置总音量，取消静音并保存到配置
+func (g *Game) setVolume(volume float64) {
+	g.config.Volume = clampVolume(math.Round(volume*10) / 10)
+	g.config.Muted = false
+	g.applyVolume()
+	g.saveConfig()
+}
+
+// 切换静音
+func (g *Game) toggleMute() {
+	g.config.Muted = !g.config.Muted
+	g.applyVolume()
+	g.saveConfig()
+	if g.config.Muted {
+		g.showMessage(tr("msg.muted"), 60)
+	} else {
+		g.showMessage(tr("msg.unmuted"), 60)
+	}
+}
+
+// 音符：频率（赫兹）、开始时间和时长（秒）
+type note struct {
+	freq     float64
+	start    float64
+	duration float64
+}
+
+// 合成音效
+func synthEffect(s Sound, pitch int) []byte {
+	switch s {
+	case SoundSlide:
+		// 短促的下滑音
+		return synthSweep(320, 180, 0.06, 0.35)
+	case SoundMerge:
+		// 方块越大音调越高，每升一级提高两个半音
+		freq := 220 * math.Pow(2, float64(pitch-1)*2/12)
+		return synthNotes([]note{
+			{freq, 0, 0.14},
+			{freq * 2, 0, 0.14},
+		}, 0.14, 0.4)
+	case SoundSpawn:
+		return synthNotes([]note{{880, 0, 0.05}}, 0.05, 0.2)
+	case SoundWin:
+		// 上行琶音
+		return synthNotes([]note{
+			{523.25, 0, 0.15},
+			{659.25, 0.12, 0.15},
+			{783.99, 0.24, 0.15},
+			{1046.5, 0.36, 0.4},
+		}, 0.76, 0.4)
+	case SoundGameOver:
+		// 下行的低沉音
+		return synthNotes([]note{
+			{392.00, 0, 0.25},
+			{329.63, 0.22, 0.25},
+			{261.63, 0.44, 0.6},
+		}, 1.04, 0.4)
+	}
+	return nil
+}
+
+// 合成背景音乐：四个和弦循环的柔和铺底，约八秒一轮
+func synthMusic() []byte {
+	chords := [][]float64{
+		{261.63, 329.63, 392.00}, // C
+		{220.00, 261.63, 329.63}, // Am
+		{174.61, 220.00, 261.63}, // F
+		{196.00, 246.94, 293.66}, // G
+	}
+	const chordLength = 2.0
+
+	var notes []note
+	for i, chord := range chords {
+		for _, freq := range chord {
+			notes = append(notes, note{freq, float64(i) * chordLength, chordLength})
+		}
+	}
+	return synthNotes(notes, chordLength*float64(len(chords)), 0.12)
+}
+
+// 将音符混合为 16 位立体声 PCM 数据，每个音符带有淡入淡出包络
+func synthNotes(notes []note, length, gain float64) []byte {
+	samples := make([]float64, int(length*audioSampleRate))
+	for _, n := range notes {
+		start := int(n.start * audioSampleRate)
+		count := int(n.duration * audioSampleRate)
+		for i := 0; i < count && start+i < len(samples); i++ {
+			t := float64(i) / audioSampleRate
+			samples[start+i] += math.Sin(2*math.Pi*n.freq*t) * envelope(t, n.duration)
+		}
+	}
+	return encodePCM(samples, gain)
+}
+
+// 合成频率从 from 滑到 to 的音
+func synthSweep(from, to, duration, gain float64) []byte {
+	samples := make([]float64, int(duration*audioSampleRate))
+	phase := 0.0
+	for i := range samples {
+		t := float64(i) / audioSampleRate
+		freq := from + (to-from)*t/duration
+		phase += 2 * math.Pi * freq / audioSampleRate
+		samples[i] = math.Sin(phase) * envelope(t, duration)
+	}
+	return encodePCM(samples, gain)
+}
+
+// 音符包络：快速淡入，结尾淡出，避免爆音
+func envelope(t, duration float64) float64 {
+	attack := math.Min(0.01, duration/4)
+	release := math.Min(0.08, duration/2)
+	switch {
+	case t < attack:
+		return t / attack
+	case t > duration-release:
+		return math.Max(0, (duration-t)/release)
+	}
+	return 1
+}
+
+// 编码为 16 位小端立体声 PCM
+func encodePCM(samples []float64, gain float64) []byte {
+	pcm := make([]byte, len(samples)*4)
+	for i, s := range samples {
+		v := int16(math.Max(-1, math.Min(1, s*gain)) * math.MaxInt16)
+		pcm[4*i] = byte(v)
+		pcm[4*i+1] = byte(v >> 8)
+		pcm[4*i+2] = byte(v)
+		pcm[4*i+3] = byte(v >> 8)
+	}
+	return pcm
+}
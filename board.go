@@ -0,0 +1,136 @@
+package main
+
+// 棋盘尺寸范围
+const (
+	minBoardSize     = 3
+	maxBoardSize     = 8
+	defaultBoardSize = 4
+)
+
+// 4x4 棋盘的方块边长，其他尺寸的方块按棋盘区域等比缩放
+const defaultTileSize = 100
+
+// 当前棋盘尺寸和方块边长（逻辑单位）
+var (
+	boardSize = defaultBoardSize
+	tileSize  = defaultTileSize
+)
+
+// Board 棋盘，按行存储方块数值，0 表示空格
+type Board [][]int
+
+// 创建空棋盘
+func newBoard(size int) Board {
+	b := make(Board, size)
+	for i := range b {
+		b[i] = make([]int, size)
+	}
+	return b
+}
+
+// 复制棋盘
+func (b Board) clone() Board {
+	c := make(Board, len(b))
+	for i := range b {
+		c[i] = append([]int(nil), b[i]...)
+	}
+	return c
+}
+
+// 判断尺寸是否受支持
+func validBoardSize(size int) bool {
+	return size >= minBoardSize && size <= maxBoardSize
+}
+
+// 判断棋盘是否为受支持尺寸的正方形
+func validSquareBoard(b Board) bool {
+	if !validBoardSize(len(b)) {
+		return false
+	}
+	for _, row := range b {
+		if len(row) != len(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// 设置棋盘尺寸，方块边长随之调整，使棋盘区域大小不变
+func setBoardSize(size int) {
+	if !validBoardSize(size) {
+		size = defaultBoardSize
+	}
+	boardSize = size
+	tileSize = (boardPixels - tileMargin*(size-1)) / size
+}
+
+// 棋盘格子区域的实际边长，方块边长取整后可能略小于 boardPixels
+func gridPixels() int {
+	return tileSize*boardSize + tileMargin*(boardSize-1)
+}
+
+// 检查棋盘是否还能移动：有空格或有相邻的相同方块
+func (b Board) canMove() bool {
+	n := len(b)
+
+	// 检查是否有空白格
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if b[i][j] == 0 {
+				return true
+			}
+		}
+	}
+
+	// 检查是否有相邻的相同数字
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// 检查右边
+			if j < n-1 && b[i][j] == b[i][j+1] {
+				return true
+			}
+			// 检查下边
+			if i < n-1 && b[i][j] == b[i+1][j] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// 四个方向中哪些会改变棋盘，按 DirectionUp、DirectionRight、DirectionDown、DirectionLeft 排列
+//
+// 某个方向可以移动，当且仅当某个方块沿该方向的前一格为空或数值相同。
+func (b Board) legalMoves() [4]bool {
+	var legal [4]bool
+	n := len(b)
+	for d := DirectionUp; d <= DirectionLeft; d++ {
+		for k := 0; k < n && !legal[d]; k++ {
+			for i := 1; i < n; i++ {
+				r, c := lineCell(d, k, i, n)
+				pr, pc := lineCell(d, k, i-1, n)
+				if b[r][c] != 0 && (b[pr][pc] == 0 || b[pr][pc] == b[r][c]) {
+					legal[d] = true
+					break
+				}
+			}
+		}
+	}
+	return legal
+}
+
+// 判断两个棋盘是否相同
+func (b Board) equal(o Board) bool {
+	if len(b) != len(o) {
+		return false
+	}
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] != o[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
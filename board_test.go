@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestLegalMoves(t *testing.T) {
+	tests := []struct {
+		name    string
+		board   Board
+		legal   [4]bool
+		canMove bool
+	}{
+		{
+			name:    "空棋盘",
+			board:   newBoard(3),
+			canMove: true,
+		},
+		{
+			name:    "左上角的单个方块",
+			board:   Board{{2, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+			legal:   [4]bool{DirectionRight: true, DirectionDown: true},
+			canMove: true,
+		},
+		{
+			name:    "靠边的一行不能再向该边移动",
+			board:   Board{{2, 4, 8, 16}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+			legal:   [4]bool{DirectionDown: true},
+			canMove: true,
+		},
+		{
+			name:  "满盘且没有相邻的相同方块",
+			board: Board{{2, 4, 2}, {4, 2, 4}, {2, 4, 2}},
+		},
+		{
+			name:    "满盘横向有相同方块",
+			board:   Board{{2, 2, 4}, {4, 8, 16}, {32, 64, 128}},
+			legal:   [4]bool{DirectionRight: true, DirectionLeft: true},
+			canMove: true,
+		},
+		{
+			name:    "满盘纵向有相同方块",
+			board:   Board{{2, 4, 8}, {2, 16, 32}, {64, 128, 256}},
+			legal:   [4]bool{DirectionUp: true, DirectionDown: true},
+			canMove: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.board.legalMoves(); got != tt.legal {
+				t.Fatalf("legalMoves() = %v, want %v", got, tt.legal)
+			}
+			if got := tt.board.canMove(); got != tt.canMove {
+				t.Fatalf("canMove() = %v, want %v", got, tt.canMove)
+			}
+			// 与实际滑动的结果保持一致
+			for d := DirectionUp; d <= DirectionLeft; d++ {
+				if _, _, moved := slideBoard(tt.board, d); moved != tt.legal[d] {
+					t.Fatalf("slideBoard(%d) moved = %v, legalMoves() = %v", d, moved, tt.legal[d])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 剪贴板命令，按平台依次尝试
+type clipboardCommand struct {
+	name string
+	args []string
+}
+
+// 写入剪贴板可用的命令
+func clipboardWriteCommands() []clipboardCommand {
+	switch runtime.GOOS {
+	case "windows":
+		return []clipboardCommand{{"clip", nil}}
+	case "darwin":
+		return []clipboardCommand{{"pbcopy", nil}}
+	default:
+		return []clipboardCommand{
+			{"wl-copy", nil},
+			{"xclip", []string{"-selection", "clipboard"}},
+			{"xsel", []string{"--clipboard", "--input"}},
+		}
+	}
+}
+
+// 读取剪贴板可用的命令
+func clipboardReadCommands() []clipboardCommand {
+	switch runtime.GOOS {
+	case "windows":
+		return []clipboardCommand{{"powershell", []string{"-NoProfile", "-Command", "Get-Clipboard"}}}
+	case "darwin":
+		return []clipboardCommand{{"pbpaste", nil}}
+	default:
+		return []clipboardCommand{
+			{"wl-paste", []string{"--no-newline"}},
+			{"xclip", []string{"-selection", "clipboard", "-o"}},
+			{"xsel", []string{"--clipboard", "--output"}},
+		}
+	}
+}
+
+// 将文本复制到系统剪贴板
+func writeClipboard(s string) error {
+	lastErr := errors.New("没有可用的剪贴板命令")
+	for _, c := range clipboardWriteCommands() {
+		if _, err := exec.LookPath(c.name); err != nil {
+			continue
+		}
+		cmd := exec.Command(c.name, c.args...)
+		cmd.Stdin = strings.NewReader(s)
+		if lastErr = cmd.Run(); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// 读取系统剪贴板中的文本
+func readClipboard() (string, error) {
+	lastErr := errors.New("没有可用的剪贴板命令")
+	for _, c := range clipboardReadCommands() {
+		if _, err := exec.LookPath(c.name); err != nil {
+			continue
+		}
+		var out bytes.Buffer
+		cmd := exec.Command(c.name, c.args...)
+		cmd.Stdout = &out
+		if lastErr = cmd.Run(); lastErr == nil {
+			return strings.TrimSpace(out.String()), nil
+		}
+	}
+	return "", lastErr
+}
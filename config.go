@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+// 用户配置文件路径，与游戏存档分开保存
+const configFilePath = "2048_config.json"
+
+// Config 用户配置
+type Config struct {
+	Language     string `json:"language"`      // 界面语言，留空时跟随系统
+	Theme        string `json:"theme"`         // 主题名称
+	TileNotation string `json:"tile_notation"` // 大数值方块的显示方式：decimal、exponent 或 compact
+
+	KeyPreset   string              `json:"key_preset"`
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"` // 动作名称 -> 按键名称，覆盖预设
+
+	InputBufferDepth       int  `json:"input_buffer_depth"`        // 动画期间最多缓冲的移动数，0 表示不缓冲
+	FinishAnimationOnInput bool `json:"finish_animation_on_input"` // 动画期间有新移动时立即结束当前动画
+
+	AnimationsEnabled bool    `json:"animations_enabled"` // 是否播放动画
+	AnimationSpeed    float64 `json:"animation_speed"`    // 动画速度倍率，2 表示两倍速
+	SlideDurationMs   int     `json:"slide_duration_ms"`  // 滑动动画时长
+	MergeDurationMs   int     `json:"merge_duration_ms"`  // 合并弹出动画时长
+	SpawnDurationMs   int     `json:"spawn_duration_ms"`  // 新方块出现动画时长
+
+	Volume      float64 `json:"volume"`       // 总音量，0 到 1
+	SoundVolume float64 `json:"sound_volume"` // 音效音量，相对于总音量
+	MusicVolume float64 `json:"music_volume"` // 背景音乐音量，相对于总音量
+	Muted       bool    `json:"muted"`        // 是否静音
+
+	BoardSize   int    `json:"board_size"`   // 新游戏的棋盘尺寸
+	RuleVariant string `json:"rule_variant"` // 规则变体名称
+
+	ShowTraining bool `json:"show_training"` // 在棋盘上显示方向危险程度和拥堵热力图
+}
+
+// 默认配置
+func defaultConfig() Config {
+	return Config{
+		Theme:             "light",
+		TileNotation:      TileNotationDecimal,
+		KeyPreset:         defaultKeyPreset,
+		InputBufferDepth:  2,
+		AnimationsEnabled: true,
+		AnimationSpeed:    1,
+		SlideDurationMs:   defaultSlideDurationMs,
+		MergeDurationMs:   defaultMergeDurationMs,
+		SpawnDurationMs:   defaultSpawnDurationMs,
+		Volume:            0.8,
+		SoundVolume:       1,
+		MusicVolume:       0.5,
+		BoardSize:         defaultBoardSize,
+		RuleVariant:       defaultRuleVariant,
+	}
+}
+
+// 加载用户配置，文件不存在或解析失败时使用默认配置
+func loadConfig() Config {
+	config := defaultConfig()
+
+	data, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取配置文件失败: %v", err)
+		}
+		return config
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Printf("解析配置文件失败: %v", err)
+		return defaultConfig()
+	}
+
+	return config
+}
+
+// 保存当前配置，失败时提示玩家
+func (g *Game) saveConfig() {
+	if err := g.config.save(); err != nil {
+		log.Printf("保存配置文件失败: %v", err)
+		g.showMessage(tr("msg.settings_save_failed"), 60)
+	}
+}
+
+// 保存用户配置
+func (c *Config) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configFilePath, data, 0644)
+}
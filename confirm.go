@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 确认对话框场景，覆盖在当前界面上，Esc 或“取消”关闭
+type confirmScene struct {
+	title   string // 标题的消息键
+	message string // 说明的消息键
+	menu    menu
+}
+
+// 打开确认对话框，选中某一项时先关闭对话框再执行，最后一项总是“取消”
+func (g *Game) openConfirm(title, message string, items ...menuItem) {
+	s := &confirmScene{title: title, message: message}
+	for _, item := range items {
+		run := item.run
+		s.menu.items = append(s.menu.items, menuItem{item.key, func(g *Game) {
+			g.popScene()
+			run(g)
+		}})
+	}
+	s.menu.items = append(s.menu.items, menuItem{"confirm.cancel", (*Game).popScene})
+	s.menu.cancel = (*Game).popScene
+	g.pushScene(s)
+}
+
+func (s *confirmScene) menuTop() float64 {
+	return layout.canvasHeight/2 + 40
+}
+
+func (s *confirmScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.popScene()
+		return
+	}
+	s.menu.update(g, s.menuTop())
+}
+
+func (s *confirmScene) draw(g *Game, screen *ebiten.Image) {
+	drawOverlay(screen, tr(s.title), tr(s.message))
+	s.menu.draw(screen, s.menuTop())
+}
+
+func (s *confirmScene) overlay() bool { return true }
+
+// 重新开始，有未结束的对局时先确认，可以选择把当前对局存入历史记录
+//
+// then 在重新开始后执行，可以为 nil。
+func (g *Game) requestReset(then func(g *Game)) {
+	restart := func(g *Game) {
+		g.resetGame()
+		g.showMessage(tr("msg.reset"), 60)
+		if then != nil {
+			then(g)
+		}
+	}
+	if !g.hasProgress() {
+		restart(g)
+		return
+	}
+
+	g.openConfirm("confirm.reset_title", "confirm.reset_message",
+		menuItem{"confirm.archive_restart", func(g *Game) {
+			g.archiveGame()
+			restart(g)
+		}},
+		menuItem{"confirm.restart", restart},
+	)
+}
+
+// 加载存档，会覆盖未结束的对局时先确认
+func (g *Game) requestLoad() {
+	if _, err := os.Stat(saveFilePath); err != nil || !g.hasProgress() {
+		g.loadGame()
+		return
+	}
+
+	g.openConfirm("confirm.load_title", "confirm.load_message",
+		menuItem{"confirm.load", func(g *Game) {
+			g.loadGame()
+		}},
+	)
+}
+
+// 退出游戏，对局未结束时先确认，退出时会自动保存
+func (g *Game) requestQuit() {
+	if !g.hasProgress() {
+		g.quitRequested = true
+		return
+	}
+
+	g.openConfirm("confirm.quit_title", "confirm.quit_message",
+		menuItem{"confirm.quit", func(g *Game) {
+			g.quitRequested = true
+		}},
+	)
+}
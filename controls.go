@@ -0,0 +1,183 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 按键设置界面布局
+const (
+	controlsListTop      = 110 // 动作列表的起始纵坐标
+	controlsMaxRowHeight = 30
+)
+
+// 动作列表的行高，画布较矮时（横屏）压缩行距
+func controlsRowHeight() int {
+	h := int(layout.canvasHeight-controlsListTop-20) / int(actionCount)
+	if h > controlsMaxRowHeight {
+		h = controlsMaxRowHeight
+	}
+	return h
+}
+
+// 按键设置界面状态
+type controlsScreen struct {
+	selected Action
+	waiting  bool // 是否正在等待按下新按键
+}
+
+// 打开按键设置界面
+func (g *Game) openControls() {
+	g.controls = controlsScreen{}
+	g.pushScene(controlsScene{})
+}
+
+// 按键设置场景
+type controlsScene struct{}
+
+func (controlsScene) update(g *Game)                     { g.updateControls() }
+func (controlsScene) draw(g *Game, screen *ebiten.Image) { g.drawControls(screen) }
+func (controlsScene) overlay() bool                      { return false }
+
+// 处理按键设置界面的输入
+func (g *Game) updateControls() {
+	c := &g.controls
+
+	// 等待新按键时，下一次按下的按键成为绑定
+	if c.waiting {
+		keys := inpututil.AppendJustPressedKeys(nil)
+		if len(keys) == 0 {
+			return
+		}
+		c.waiting = false
+		if keys[0] == ebiten.KeyEscape {
+			return
+		}
+		g.rebindKey(c.selected, keys[0])
+		return
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		c.selected = (c.selected + actionCount - 1) % actionCount
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		c.selected = (c.selected + 1) % actionCount
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		g.cycleKeyPreset(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		g.cycleKeyPreset(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		c.waiting = true
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace), inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		// 恢复该动作的预设按键
+		delete(g.config.KeyBindings, actionNames[c.selected])
+		g.applyKeyConfig()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape), g.keys.justPressed(ActionControls):
+		g.popScene()
+	}
+
+	// 鼠标点击选中动作
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		_, y := cursorPosition()
+		row := (y - controlsListTop) / controlsRowHeight()
+		if y >= controlsListTop && row < int(actionCount) {
+			if Action(row) == c.selected {
+				c.waiting = true
+			} else {
+				c.selected = Action(row)
+			}
+		}
+	}
+}
+
+// 将按键绑定到动作，并从其他动作中移除该按键以避免冲突
+func (g *Game) rebindKey(a Action, key ebiten.Key) {
+	if g.config.KeyBindings == nil {
+		g.config.KeyBindings = map[string][]string{}
+	}
+
+	for other := Action(0); other < actionCount; other++ {
+		if other == a {
+			continue
+		}
+		keys := g.keys[other]
+		for i, k := range keys {
+			if k == key {
+				remaining := append(append([]ebiten.Key(nil), keys[:i]...), keys[i+1:]...)
+				g.config.KeyBindings[actionNames[other]] = keyNames(remaining)
+				break
+			}
+		}
+	}
+
+	g.config.KeyBindings[actionNames[a]] = []string{key.String()}
+	g.applyKeyConfig()
+}
+
+// 切换按键预设，自定义按键会被清除
+func (g *Game) cycleKeyPreset(delta int) {
+	current := 0
+	for i, p := range keyPresets {
+		if p.name == g.config.KeyPreset {
+			current = i
+		}
+	}
+	next := (current + delta + len(keyPresets)) % len(keyPresets)
+
+	g.config.KeyPreset = keyPresets[next].name
+	g.config.KeyBindings = nil
+	g.applyKeyConfig()
+}
+
+// 重新计算按键绑定并保存配置
+func (g *Game) applyKeyConfig() {
+	g.keys = resolveKeyBindings(g.config.KeyPreset, g.config.KeyBindings)
+	g.saveConfig()
+}
+
+// 按键名称列表
+func keyNames(keys []ebiten.Key) []string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	return names
+}
+
+// 绘制按键设置界面
+func (g *Game) drawControls(screen *ebiten.Image) {
+	c := &g.controls
+
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	drawTextCentered(screen, tr("controls.title"), titleFont, centerX, 60, currentTheme.Text)
+
+	preset := findKeyPreset(g.config.KeyPreset)
+	drawTextCentered(screen, tr("controls.preset", preset.label()), scoreFont, centerX, 90, currentTheme.Text)
+
+	rowHeight := controlsRowHeight()
+	for a := Action(0); a < actionCount; a++ {
+		y := float64(controlsListTop + int(a)*rowHeight)
+
+		if a == c.selected {
+			drawRect(screen, boardMargin, y, layout.canvasWidth-2*boardMargin, float64(rowHeight-4), currentTheme.Board)
+		}
+
+		col := currentTheme.Text
+		if a == c.selected {
+			col = currentTheme.TextLight
+		}
+
+		binding := g.keys.label(a)
+		if a == c.selected && c.waiting {
+			binding = tr("controls.waiting")
+		}
+
+		baseline := y + float64(rowHeight-11)
+		drawText(screen, a.label(), scoreFont, boardMargin+10, baseline, col)
+		bindingWidth, _ := textSize(scoreFont, binding)
+		drawText(screen, binding, scoreFont, layout.canvasWidth-boardMargin-10-bindingWidth, baseline, col)
+	}
+
+	drawTextCentered(screen, tr("controls.help"), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+}
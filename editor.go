@@ -0,0 +1,381 @@
+package main
+
+import (
+	"image/color"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 局面导出文件路径
+const positionFilePath = "2048_position.txt"
+
+// 编辑器中选中格子的高亮颜色
+var editorSelectColor = color.RGBA{246, 94, 59, 255}
+
+// TileSpawn 指定下一个生成方块的位置和数值
+type TileSpawn struct {
+	Row   int `json:"row"`
+	Col   int `json:"col"`
+	Value int `json:"value"`
+}
+
+// Editor 棋盘编辑器状态，用于摆放残局和谜题
+type Editor struct {
+	board     Board
+	selRow    int
+	selCol    int
+	nextSpawn *TileSpawn
+	score     int  // 导入局面时带入的分数
+	side      byte // 导入局面时带入的行动方
+}
+
+// 校验编辑后的局面是否可以开始游戏
+func validateBoard(board Board, spawn *TileSpawn) error {
+	tiles := 0
+	for i := range board {
+		for j := range board[i] {
+			if board[i][j] == 0 {
+				continue
+			}
+			exp := tileExponent(board[i][j])
+			if exp < 1 || exp > maxTileExponent {
+				return messageError("error.invalid_tile")
+			}
+			tiles++
+		}
+	}
+	if tiles == 0 {
+		return messageError("error.empty_board")
+	}
+
+	if spawn != nil {
+		if spawn.Row >= len(board) || spawn.Col >= len(board) || board[spawn.Row][spawn.Col] != 0 {
+			return messageError("error.spawn_not_empty")
+		}
+		if spawn.Value != 2 && spawn.Value != 4 {
+			return messageError("error.invalid_spawn")
+		}
+	}
+
+	check := Game{board: board}
+	if !check.canMove() {
+		return messageError("error.no_moves")
+	}
+
+	return nil
+}
+
+// 校验完整局面，等待生成方块的局面必须留有空格
+func validatePosition(pos Position) error {
+	if err := validateBoard(pos.Board, pos.NextSpawn); err != nil {
+		return err
+	}
+
+	if pos.Side == SideSpawn {
+		for i := range pos.Board {
+			for j := range pos.Board[i] {
+				if pos.Board[i][j] == 0 {
+					return nil
+				}
+			}
+		}
+		return messageError("error.no_spawn_cell")
+	}
+
+	return nil
+}
+
+// 计算屏幕坐标对应的棋盘格子
+func cellAt(x, y int) (row, col int, ok bool) {
+	boardX := layout.boardX
+	boardY := layout.boardY
+
+	if x < boardX || y < boardY {
+		return 0, 0, false
+	}
+	col = (x - boardX) / (tileSize + tileMargin)
+	row = (y - boardY) / (tileSize + tileMargin)
+	if row >= boardSize || col >= boardSize {
+		return 0, 0, false
+	}
+	return row, col, true
+}
+
+// 进入编辑模式，以当前棋盘为起点
+func (g *Game) openEditor() {
+	g.editor = Editor{
+		board: g.board.clone(),
+		score: g.score,
+		side:  SidePlayer,
+	}
+	if g.nextSpawn != nil {
+		spawn := *g.nextSpawn
+		g.editor.nextSpawn = &spawn
+	}
+	g.pushScene(editorScene{})
+	g.showMessage(tr("msg.editor_mode"), 60)
+}
+
+// 棋盘编辑场景
+type editorScene struct{}
+
+func (editorScene) update(g *Game)                     { g.updateEditor() }
+func (editorScene) draw(g *Game, screen *ebiten.Image) { g.drawEditor(screen) }
+func (editorScene) overlay() bool                      { return false }
+
+// 处理编辑模式下的输入
+func (g *Game) updateEditor() {
+	ed := &g.editor
+
+	// 鼠标左键选中格子，再次点击提升数值
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if row, col, ok := cellAt(cursorPosition()); ok {
+			if row == ed.selRow && col == ed.selCol {
+				ed.stepTile(1)
+			} else {
+				ed.selRow, ed.selCol = row, col
+			}
+		}
+	}
+
+	// 鼠标右键清除格子
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		if row, col, ok := cellAt(cursorPosition()); ok {
+			ed.selRow, ed.selCol = row, col
+			ed.setTile(0)
+		}
+	}
+
+	// 滚轮调整数值
+	if _, dy := ebiten.Wheel(); dy > 0 {
+		ed.stepTile(1)
+	} else if dy < 0 {
+		ed.stepTile(-1)
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		ed.selRow = (ed.selRow + boardSize - 1) % boardSize
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		ed.selRow = (ed.selRow + 1) % boardSize
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		ed.selCol = (ed.selCol + boardSize - 1) % boardSize
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		ed.selCol = (ed.selCol + 1) % boardSize
+	case inpututil.IsKeyJustPressed(ebiten.KeyEqual), inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd):
+		ed.stepTile(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyMinus), inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract):
+		ed.stepTile(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDigit0),
+		inpututil.IsKeyJustPressed(ebiten.KeyBackspace),
+		inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		ed.setTile(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyN):
+		g.toggleEditorSpawn()
+	case inpututil.IsKeyJustPressed(ebiten.KeyC):
+		ed.board = newBoard(boardSize)
+		ed.nextSpawn = nil
+		ed.score = 0
+	case inpututil.IsKeyJustPressed(ebiten.KeyX):
+		g.exportEditorBoard()
+	case inpututil.IsKeyJustPressed(ebiten.KeyI):
+		g.importEditorBoard()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		g.applyEditor()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		// 导入的局面可能改变了棋盘尺寸，恢复为游戏棋盘的尺寸
+		g.popScene()
+		setBoardSize(len(g.board))
+		g.showMessage(tr("msg.editor_cancelled"), 60)
+	default:
+		// 数字键 1-9 直接设置为 2^n
+		for k := ebiten.KeyDigit1; k <= ebiten.KeyDigit9; k++ {
+			if inpututil.IsKeyJustPressed(k) {
+				ed.setTile(1 << (int(k-ebiten.KeyDigit1) + 1))
+				break
+			}
+		}
+	}
+}
+
+// 设置选中格子的数值
+func (ed *Editor) setTile(value int) {
+	ed.board[ed.selRow][ed.selCol] = value
+	// 格子被占用后不能再作为生成位置
+	if value != 0 && ed.nextSpawn != nil && ed.nextSpawn.Row == ed.selRow && ed.nextSpawn.Col == ed.selCol {
+		ed.nextSpawn = nil
+	}
+}
+
+// 按指数步进调整选中格子的数值
+func (ed *Editor) stepTile(delta int) {
+	exp := tileExponent(ed.board[ed.selRow][ed.selCol]) + delta
+	if exp < 0 {
+		exp = 0
+	}
+	if exp > maxTileExponent {
+		exp = maxTileExponent
+	}
+	if exp == 0 {
+		ed.setTile(0)
+	} else {
+		ed.setTile(1 << exp)
+	}
+}
+
+// 在选中格子上切换下一个生成方块：无 -> 2 -> 4 -> 无
+func (g *Game) toggleEditorSpawn() {
+	ed := &g.editor
+	if ed.board[ed.selRow][ed.selCol] != 0 {
+		g.showMessage(tr("error.spawn_not_empty"), 60)
+		return
+	}
+
+	spawn := ed.nextSpawn
+	switch {
+	case spawn == nil || spawn.Row != ed.selRow || spawn.Col != ed.selCol:
+		ed.nextSpawn = &TileSpawn{Row: ed.selRow, Col: ed.selCol, Value: 2}
+	case spawn.Value == 2:
+		spawn.Value = 4
+	default:
+		ed.nextSpawn = nil
+	}
+}
+
+// 编辑中的局面
+func (ed *Editor) position() Position {
+	return Position{
+		Board:     ed.board.clone(),
+		Side:      ed.side,
+		Score:     ed.score,
+		NextSpawn: ed.nextSpawn,
+	}
+}
+
+// 导出编辑中的局面到剪贴板，剪贴板不可用时写入文件
+func (g *Game) exportEditorBoard() {
+	notation := formatPosition(g.editor.position())
+	log.Printf("导出局面: %s", notation)
+
+	err := writeClipboard(notation)
+	if err == nil {
+		g.showMessage(tr("msg.position_copied"), 60)
+		return
+	}
+	log.Printf("复制到剪贴板失败: %v", err)
+
+	if err := ioutil.WriteFile(positionFilePath, []byte(notation+"\n"), 0644); err != nil {
+		log.Printf("导出局面失败: %v", err)
+		g.showMessage(tr("msg.export_position_failed"), 60)
+		return
+	}
+	g.showMessage(tr("msg.position_exported"), 60)
+}
+
+// 从剪贴板导入局面到编辑器，剪贴板中没有有效局面时读取文件
+func (g *Game) importEditorBoard() {
+	pos, err := readPositionFromClipboard()
+	if err != nil {
+		log.Printf("从剪贴板导入局面失败: %v", err)
+
+		data, readErr := ioutil.ReadFile(positionFilePath)
+		if readErr != nil {
+			log.Printf("读取局面文件失败: %v", readErr)
+			g.showMessage(tr("msg.import_position_failed"), 60)
+			return
+		}
+		pos, err = parsePosition(strings.TrimSpace(string(data)))
+		if err != nil {
+			log.Printf("解析局面失败: %v", err)
+			g.showMessage(tr("msg.import_position_failed"), 60)
+			return
+		}
+	}
+
+	// 导入的局面可以是其他尺寸的棋盘
+	setBoardSize(len(pos.Board))
+	g.editor.board = pos.Board
+	g.editor.selRow = min(g.editor.selRow, boardSize-1)
+	g.editor.selCol = min(g.editor.selCol, boardSize-1)
+	g.editor.nextSpawn = pos.NextSpawn
+	g.editor.score = pos.Score
+	g.editor.side = pos.Side
+	g.showMessage(tr("msg.position_imported"), 60)
+}
+
+// 读取剪贴板并解析为局面
+func readPositionFromClipboard() (Position, error) {
+	s, err := readClipboard()
+	if err != nil {
+		return Position{}, err
+	}
+	return parsePosition(s)
+}
+
+// 校验并从编辑后的局面开始游戏
+func (g *Game) applyEditor() {
+	ed := &g.editor
+	pos := ed.position()
+	if err := validatePosition(pos); err != nil {
+		g.showMessage(err.Error(), 90)
+		return
+	}
+
+	g.setPosition(pos)
+	g.popScene()
+	g.saveGame(false)
+	g.showMessage(tr("msg.practice_started"), 60)
+}
+
+// 绘制编辑模式界面
+func (g *Game) drawEditor(screen *ebiten.Image) {
+	ed := &g.editor
+
+	screen.Fill(currentTheme.Background)
+	drawTextCentered(screen, "2048", titleFont, layout.infoX, float64(layout.titleY), currentTheme.Text)
+
+	// 绘制编辑说明
+	lines := []string{
+		tr("editor.help1"),
+		tr("editor.help2"),
+		tr("editor.help3"),
+	}
+	// 横屏时说明显示在侧边面板，每项一行
+	var hints []string
+	for _, line := range lines {
+		hints = append(hints, layout.hintLines(line)...)
+	}
+	for i, line := range hints {
+		drawTextCentered(screen, line, scoreFont, layout.infoX, float64(layout.titleY+40+i*22), currentTheme.Text)
+	}
+
+	// 复用棋盘绘制
+	drawBoard(screen, ed.board)
+	drawTiles(screen, ed.board)
+
+	boardX := layout.boardX
+	boardY := layout.boardY
+
+	// 绘制下一个生成方块的预览（半透明）
+	if ed.nextSpawn != nil {
+		x := boardX + ed.nextSpawn.Col*(tileSize+tileMargin)
+		y := boardY + ed.nextSpawn.Row*(tileSize+tileMargin)
+		drawTile(screen, ed.nextSpawn.Value, x, y)
+		overlay := currentTheme.EmptyTile
+		overlay.A = 150
+		drawRect(screen, float64(x), float64(y), float64(tileSize), float64(tileSize), overlay)
+	}
+
+	// 绘制选中格子的边框
+	x := float64(boardX + ed.selCol*(tileSize+tileMargin))
+	y := float64(boardY + ed.selRow*(tileSize+tileMargin))
+	border := 4.0
+	drawRect(screen, x, y, float64(tileSize), border, editorSelectColor)
+	drawRect(screen, x, y+float64(tileSize)-border, float64(tileSize), border, editorSelectColor)
+	drawRect(screen, x, y, border, float64(tileSize), editorSelectColor)
+	drawRect(screen, x+float64(tileSize)-border, y, border, float64(tileSize), editorSelectColor)
+}
@@ -0,0 +1,199 @@
+package main
+
+import "math"
+
+// 局面评估的权重
+const (
+	evalEmptyWeight     = 2.7  // 每个空格
+	evalMonotonicWeight = 1.0  // 行列单调性的扣分
+	evalSmoothWeight    = 0.1  // 相邻方块差距的扣分
+	evalCornerWeight    = 1.0  // 最大方块在角落时按其指数加分
+	evalGameOverPenalty = 1000 // 无路可走的局面
+)
+
+// 按方向滑动棋盘，返回新棋盘、获得的分数和棋盘是否改变，不修改原棋盘
+func slideBoard(b Board, direction int) (Board, int, bool) {
+	n := len(b)
+	out := newBoard(n)
+	line := make([]int, n)
+	gained := 0
+	moved := false
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			r, c := lineCell(direction, k, i, n)
+			line[i] = b[r][c]
+		}
+		merged, score := slideLine(line)
+		gained += score
+		for i := 0; i < n; i++ {
+			r, c := lineCell(direction, k, i, n)
+			out[r][c] = merged[i]
+			if merged[i] != line[i] {
+				moved = true
+			}
+		}
+	}
+	return out, gained, moved
+}
+
+// 第 k 行或列上第 i 个格子的位置，i 为 0 时位于滑动方向的最前端
+func lineCell(direction, k, i, n int) (int, int) {
+	switch direction {
+	case DirectionUp:
+		return i, k
+	case DirectionDown:
+		return n - 1 - i, k
+	case DirectionLeft:
+		return k, i
+	default:
+		return k, n - 1 - i
+	}
+}
+
+// 按方向滑动时合并出的方块数值，不修改原棋盘
+func slideMerges(b Board, direction int) []int {
+	n := len(b)
+	var merges []int
+	for k := 0; k < n; k++ {
+		prev := 0
+		for i := 0; i < n; i++ {
+			r, c := lineCell(direction, k, i, n)
+			v := b[r][c]
+			if v == 0 {
+				continue
+			}
+			if v == prev {
+				merges = append(merges, v*2)
+				prev = 0
+			} else {
+				prev = v
+			}
+		}
+	}
+	return merges
+}
+
+// 将一行方块向前滑动并合并，每个方块每次最多合并一次
+func slideLine(line []int) ([]int, int) {
+	var tiles []int
+	for _, v := range line {
+		if v != 0 {
+			tiles = append(tiles, v)
+		}
+	}
+
+	out := make([]int, len(line))
+	score := 0
+	pos := 0
+	for i := 0; i < len(tiles); i++ {
+		if i+1 < len(tiles) && tiles[i] == tiles[i+1] {
+			out[pos] = tiles[i] * 2
+			score += out[pos]
+			i++
+		} else {
+			out[pos] = tiles[i]
+		}
+		pos++
+	}
+	return out, score
+}
+
+// 局面的启发式评分：空格越多、行列越单调、相邻方块越接近、最大方块在角落时越高
+func evaluateBoard(b Board) float64 {
+	n := len(b)
+	empty := 0
+	monotonic := 0.0
+	smooth := 0.0
+
+	level := func(v int) float64 {
+		if v == 0 {
+			return 0
+		}
+		return math.Log2(float64(v))
+	}
+
+	for k := 0; k < n; k++ {
+		rowInc, rowDec, colInc, colDec := 0.0, 0.0, 0.0, 0.0
+		for i := 0; i < n; i++ {
+			if b[k][i] == 0 {
+				empty++
+			}
+			if i == n-1 {
+				continue
+			}
+
+			a, c := level(b[k][i]), level(b[k][i+1])
+			if a < c {
+				rowInc += c - a
+			} else {
+				rowDec += a - c
+			}
+			if a > 0 && c > 0 {
+				smooth += math.Abs(a - c)
+			}
+
+			a, c = level(b[i][k]), level(b[i+1][k])
+			if a < c {
+				colInc += c - a
+			} else {
+				colDec += a - c
+			}
+			if a > 0 && c > 0 {
+				smooth += math.Abs(a - c)
+			}
+		}
+		monotonic += math.Min(rowInc, rowDec) + math.Min(colInc, colDec)
+	}
+
+	score := evalEmptyWeight*float64(empty) - evalMonotonicWeight*monotonic - evalSmoothWeight*smooth
+	if b.maxInCorner() {
+		score += evalCornerWeight * level(b.maxTile())
+	}
+	return score
+}
+
+// 滑动后棋盘的期望评分：对所有空格上生成2或4的结果取平均
+func expectedValue(b Board, fourChance float64) float64 {
+	total, count := 0.0, 0
+	for r := range b {
+		for c := range b[r] {
+			if b[r][c] != 0 {
+				continue
+			}
+			b[r][c] = 2
+			v := (1 - fourChance) * spawnValue(b)
+			b[r][c] = 4
+			v += fourChance * spawnValue(b)
+			b[r][c] = 0
+			total += v
+			count++
+		}
+	}
+	if count == 0 {
+		return spawnValue(b)
+	}
+	return total / float64(count)
+}
+
+// 生成方块后局面的评分，无路可走时大幅扣分
+func spawnValue(b Board) float64 {
+	if b.canMove() {
+		return evaluateBoard(b)
+	}
+	return evaluateBoard(b) - evalGameOverPenalty
+}
+
+// 评估四个方向的走法，不能移动的方向 ok 为 false
+func evaluateMoves(b Board, fourChance float64) (values [4]float64, ok [4]bool) {
+	legal := b.legalMoves()
+	for d := DirectionUp; d <= DirectionLeft; d++ {
+		if !legal[d] {
+			continue
+		}
+		after, _, _ := slideBoard(b, d)
+		values[d] = expectedValue(after, fourChance)
+		ok[d] = true
+	}
+	return values, ok
+}
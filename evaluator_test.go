@@ -0,0 +1,137 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlideBoard(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     Board
+		direction int
+		want      Board
+		score     int
+		moved     bool
+	}{
+		{
+			name:      "向左合并",
+			board:     Board{{2, 2, 4, 4}, {0, 0, 0, 0}, {2, 0, 2, 0}, {4, 4, 4, 0}},
+			direction: DirectionLeft,
+			want:      Board{{4, 8, 0, 0}, {0, 0, 0, 0}, {4, 0, 0, 0}, {8, 4, 0, 0}},
+			score:     24,
+			moved:     true,
+		},
+		{
+			name:      "每个方块只合并一次",
+			board:     Board{{2, 2, 2, 2}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+			direction: DirectionRight,
+			want:      Board{{0, 0, 4, 4}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+			score:     8,
+			moved:     true,
+		},
+		{
+			name:      "向上靠近滑动方向的先合并",
+			board:     Board{{2, 0, 0}, {2, 4, 0}, {2, 0, 4}},
+			direction: DirectionUp,
+			want:      Board{{4, 4, 4}, {2, 0, 0}, {0, 0, 0}},
+			score:     4,
+			moved:     true,
+		},
+		{
+			name:      "向下",
+			board:     Board{{8, 0, 0}, {8, 2, 0}, {0, 0, 0}},
+			direction: DirectionDown,
+			want:      Board{{0, 0, 0}, {0, 0, 0}, {16, 2, 0}},
+			score:     16,
+			moved:     true,
+		},
+		{
+			name:      "不能移动的方向",
+			board:     Board{{2, 4, 0}, {8, 0, 0}, {0, 0, 0}},
+			direction: DirectionLeft,
+			want:      Board{{2, 4, 0}, {8, 0, 0}, {0, 0, 0}},
+			score:     0,
+			moved:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.board.clone()
+			got, score, moved := slideBoard(tt.board, tt.direction)
+			if !reflect.DeepEqual(got, tt.want) || score != tt.score || moved != tt.moved {
+				t.Fatalf("slideBoard() = %v, %d, %v, want %v, %d, %v", got, score, moved, tt.want, tt.score, tt.moved)
+			}
+			if !reflect.DeepEqual(tt.board, before) {
+				t.Fatalf("slideBoard() 修改了原棋盘: %v", tt.board)
+			}
+		})
+	}
+}
+
+func TestSlideMerges(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     Board
+		direction int
+		want      []int
+	}{
+		{"没有合并", Board{{2, 4, 0}, {0, 0, 0}, {0, 0, 0}}, DirectionLeft, nil},
+		{"每行分别合并", Board{{2, 2, 4, 4}, {0, 0, 0, 0}, {8, 0, 8, 0}, {0, 0, 0, 0}}, DirectionLeft, []int{4, 8, 16}},
+		{"三个相同只合并前两个", Board{{2, 0, 0}, {2, 0, 0}, {2, 0, 0}}, DirectionDown, []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slideMerges(tt.board, tt.direction); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("slideMerges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		board Board
+		ok    [4]bool
+	}{
+		{
+			name:  "只能向下",
+			board: Board{{2, 4, 8, 16}, {4, 8, 16, 32}, {8, 16, 32, 64}, {0, 0, 0, 0}},
+			ok:    [4]bool{DirectionDown: true},
+		},
+		{
+			name:  "无路可走",
+			board: Board{{2, 4, 2}, {4, 2, 4}, {2, 4, 2}},
+		},
+		{
+			name:  "四个方向都能走",
+			board: Board{{0, 0, 0}, {0, 2, 0}, {0, 0, 0}},
+			ok:    [4]bool{true, true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, ok := evaluateMoves(tt.board, 0.1)
+			if ok != tt.ok {
+				t.Fatalf("evaluateMoves() ok = %v, want %v", ok, tt.ok)
+			}
+			for d := range values {
+				if !ok[d] && values[d] != 0 {
+					t.Fatalf("evaluateMoves() 不能移动的方向 %d 评分为 %v", d, values[d])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateBoardPrefersCorner(t *testing.T) {
+	corner := Board{{64, 8, 2, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	center := Board{{0, 0, 0, 0}, {0, 64, 8, 2}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	if evaluateBoard(corner) <= evaluateBoard(center) {
+		t.Fatalf("evaluateBoard() 角落 %v 不高于中间 %v", evaluateBoard(corner), evaluateBoard(center))
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+// moveEvent 一次有效移动的结果，移动动画结束后依次交给各个监听者
+type moveEvent struct {
+	direction int
+	merges    []int // 本次移动合并出的方块数值
+	gained    int   // 本次移动获得的分数
+	moves     int   // 本局已走的步数
+	maxTile   int   // 移动后最大的方块
+	won       bool  // 本次移动达到胜利目标
+	milestone int   // 本次移动达到的新里程碑，没有时为 0
+	gameOver  bool  // 本次移动后无路可走
+}
+
+// 移动事件的监听者
+var moveEventHandlers = []func(g *Game, e moveEvent){
+	(*Game).checkAchievements,
+}
+
+// 分发移动事件
+func (g *Game) dispatchMoveEvent(e moveEvent) {
+	for _, handle := range moveEventHandlers {
+		handle(g, e)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// 字体回退链、当前字体的缩放比例和按字号缓存的字体
+var (
+	uiFonts        []*opentype.Font
+	normalFontSize = 16.0
+	fontScale      float64
+	fontFaces      = map[float64]font.Face{}
+)
+
+// 加载字体：中文字体在前，拉丁字体在后，缺少的字形依次向后查找
+func loadFonts() {
+	if tt := loadFontAsset(chineseFontFile); tt != nil {
+		uiFonts = append(uiFonts, tt)
+	}
+
+	tt, err := opentype.Parse(fonts.MPlus1pRegular_ttf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	uiFonts = append(uiFonts, tt)
+
+	// 只有拉丁字体时使用较小的普通字号
+	if len(uiFonts) == 1 {
+		normalFontSize = 12
+	}
+
+	setFontScale(1)
+}
+
+// 解析字体资源，用户提供的字体无法解析时使用内置字体
+func loadFontAsset(name string) *opentype.Font {
+	data, err := readAsset(name)
+	if err == nil {
+		tt, err := opentype.Parse(data)
+		if err == nil {
+			return tt
+		}
+		log.Printf("无法解析字体 %s，使用内置字体: %v", name, err)
+	} else {
+		log.Printf("无法加载字体 %s: %v", name, err)
+	}
+
+	data, err = readBuiltinAsset(name)
+	if err != nil {
+		log.Printf("无法加载内置字体 %s: %v", name, err)
+		return nil
+	}
+	tt, err := opentype.Parse(data)
+	if err != nil {
+		log.Printf("无法解析内置字体 %s: %v", name, err)
+		return nil
+	}
+	return tt
+}
+
+// 按缩放比例更新字体，使文字在任意窗口尺寸和高分屏下保持清晰
+func setFontScale(scale float64) {
+	if scale == fontScale {
+		return
+	}
+	fontScale = scale
+
+	// 旧缩放比例下的字体不再使用
+	fontFaces = map[float64]font.Face{}
+
+	normalFont = fontFace(normalFontSize * scale)
+	boldFont = fontFace(16 * scale)
+	titleFont = fontFace(32 * scale)
+	scoreFont = fontFace(14 * scale)
+}
+
+// 获取指定字号（设备像素）的字体，同一字号只创建一次
+func fontFace(size float64) font.Face {
+	if face, ok := fontFaces[size]; ok {
+		return face
+	}
+
+	faces := make([]font.Face, 0, len(uiFonts))
+	for _, tt := range uiFonts {
+		face, err := opentype.NewFace(tt, &opentype.FaceOptions{
+			Size:    size,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		faces = append(faces, face)
+	}
+
+	var face font.Face = faces[0]
+	if len(faces) > 1 {
+		face = &fallbackFace{faces: faces}
+	}
+	fontFaces[size] = face
+	return face
+}
+
+// fallbackFace 组合多个字体，每个字符使用第一个包含该字形的字体
+type fallbackFace struct {
+	faces []font.Face
+}
+
+// 查找包含字形的字体，都不包含时使用第一个字体
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	for _, face := range f.faces {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return face
+		}
+	}
+	return f.faces[0]
+}
+
+func (f *fallbackFace) Close() error {
+	for _, face := range f.faces {
+		face.Close()
+	}
+	return nil
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+// 两个字符来自不同字体时不做字距调整
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.faceFor(r0)
+	if face != f.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+// 行高等度量使用第一个字体
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
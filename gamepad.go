@@ -0,0 +1,134 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 摇杆阈值
+const (
+	gamepadStickDeadZone = 0.5 // 超过该值才视为推动摇杆
+	gamepadStickRelease  = 0.3 // 回到该值以内才视为摇杆回中
+)
+
+// 手柄方向键与移动动作的对应关系
+var gamepadDirectionButtons = map[ebiten.StandardGamepadButton]Action{
+	ebiten.StandardGamepadButtonLeftTop:    ActionMoveUp,
+	ebiten.StandardGamepadButtonLeftRight:  ActionMoveRight,
+	ebiten.StandardGamepadButtonLeftBottom: ActionMoveDown,
+	ebiten.StandardGamepadButtonLeftLeft:   ActionMoveLeft,
+}
+
+// 手柄功能键与动作的对应关系：A继续，B撤销，Y新游戏
+var gamepadActionButtons = map[ebiten.StandardGamepadButton]Action{
+	ebiten.StandardGamepadButtonRightBottom: ActionContinue,
+	ebiten.StandardGamepadButtonRightRight:  ActionUndo,
+	ebiten.StandardGamepadButtonRightTop:    ActionReset,
+}
+
+// 单个手柄的状态
+type gamepadState struct {
+	stickHeld bool // 摇杆是否仍处于推动状态，回中前不重复触发
+}
+
+// 读取手柄输入：连接提示、方向键、左摇杆和功能键，本帧触发的动作交给当前场景处理
+func (g *Game) updateGamepads() {
+	g.padActions = g.padActions[:0]
+	if g.gamepads == nil {
+		g.gamepads = map[ebiten.GamepadID]*gamepadState{}
+	}
+
+	// 热插拔提示
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		log.Printf("手柄已连接: %s", ebiten.GamepadName(id))
+		g.gamepads[id] = &gamepadState{}
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			g.showMessage(tr("msg.gamepad_connected"), 60)
+		} else {
+			g.showMessage(tr("msg.gamepad_unsupported"), 60)
+		}
+	}
+	for id := range g.gamepads {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			delete(g.gamepads, id)
+			g.showMessage(tr("msg.gamepad_disconnected"), 60)
+		}
+	}
+
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		state, ok := g.gamepads[id]
+		if !ok {
+			state = &gamepadState{}
+			g.gamepads[id] = state
+		}
+
+		if dir, ok := gamepadDirection(id, state); ok {
+			g.padActions = append(g.padActions, dir)
+			continue
+		}
+
+		for button, a := range gamepadActionButtons {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				g.padActions = append(g.padActions, a)
+				break
+			}
+		}
+	}
+}
+
+// 本帧是否用按键或手柄触发了该动作
+func (g *Game) actionPressed(a Action) bool {
+	return g.keys.justPressed(a) || g.padPressed(a)
+}
+
+// 本帧是否有手柄触发了该动作
+func (g *Game) padPressed(a Action) bool {
+	for _, pa := range g.padActions {
+		if pa == a {
+			return true
+		}
+	}
+	return false
+}
+
+// 读取手柄方向键和左摇杆，返回本帧新触发的移动动作
+func gamepadDirection(id ebiten.GamepadID, state *gamepadState) (Action, bool) {
+	for button, dir := range gamepadDirectionButtons {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return dir, true
+		}
+	}
+
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	magnitude := math.Hypot(x, y)
+
+	// 摇杆回中后才允许下一次触发，避免按住时连续移动
+	if state.stickHeld {
+		if magnitude < gamepadStickRelease {
+			state.stickHeld = false
+		}
+		return 0, false
+	}
+	if magnitude < gamepadStickDeadZone {
+		return 0, false
+	}
+	state.stickHeld = true
+
+	if math.Abs(x) > math.Abs(y) {
+		if x > 0 {
+			return ActionMoveRight, true
+		}
+		return ActionMoveLeft, true
+	}
+	if y > 0 {
+		return ActionMoveDown, true
+	}
+	return ActionMoveUp, true
+}
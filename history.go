@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+// 历史记录文件路径
+const historyFilePath = "2048_history.json"
+
+// 最多保存的历史对局数，超出时丢弃最早的对局
+const maxHistoryRecords = 200
+
+// MoveRecord 一步移动：方向和随后生成的方块
+type MoveRecord struct {
+	Direction int        `json:"dir"`
+	Spawn     *TileSpawn `json:"spawn,omitempty"`
+}
+
+// GameRecord 一局游戏的记录，可以从初始局面逐步回放
+type GameRecord struct {
+	StartedAt    time.Time     `json:"started_at"`
+	EndedAt      time.Time     `json:"ended_at,omitempty"`
+	Rules        string        `json:"rules"`
+	Practice     bool          `json:"practice,omitempty"` // 从编辑器或记谱开始的练习局，不进入排行榜
+	InitialBoard Board         `json:"initial_board"`
+	InitialScore int           `json:"initial_score,omitempty"`
+	Moves        []MoveRecord  `json:"moves"`
+	Score        int           `json:"score"`
+	MaxTile      int           `json:"max_tile"`
+	Won          bool          `json:"won"`
+	Milestone    int           `json:"milestone,omitempty"` // 达到的最高里程碑
+	PlayTime     time.Duration `json:"play_time"`           // 实际游戏时间（纳秒），暂停和菜单中不计时
+	Undos        int           `json:"undos,omitempty"`
+	LeftCorner   bool          `json:"left_corner,omitempty"` // 最大方块曾经离开角落
+}
+
+// 棋盘中最大的方块
+func (b Board) maxTile() int {
+	max := 0
+	for _, row := range b {
+		for _, v := range row {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	return max
+}
+
+// 记录能否回放：初始棋盘尺寸受支持，每步的方向和新方块位置都有效
+func (r GameRecord) valid() bool {
+	if !validSquareBoard(r.InitialBoard) {
+		return false
+	}
+	n := len(r.InitialBoard)
+	for _, m := range r.Moves {
+		if !validDirection(m.Direction) {
+			return false
+		}
+		if s := m.Spawn; s != nil && (s.Row < 0 || s.Row >= n || s.Col < 0 || s.Col >= n) {
+			return false
+		}
+	}
+	return true
+}
+
+// 把记录的新方块放到滑动后的棋盘上，位置超出棋盘时忽略
+func (m MoveRecord) placeSpawn(b Board) {
+	if s := m.Spawn; s != nil && s.Row >= 0 && s.Row < len(b) && s.Col >= 0 && s.Col < len(b) {
+		b[s.Row][s.Col] = s.Value
+	}
+}
+
+// 加载历史记录，文件不存在或损坏时返回空记录
+func loadHistory() []GameRecord {
+	data, err := ioutil.ReadFile(historyFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取历史记录失败: %v", err)
+		}
+		return nil
+	}
+
+	var records []GameRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Printf("解析历史记录失败: %v", err)
+		return nil
+	}
+
+	// 丢弃无法回放的记录
+	valid := records[:0]
+	for _, r := range records {
+		if r.valid() {
+			valid = append(valid, r)
+		}
+	}
+	return valid
+}
+
+// 保存历史记录
+func saveHistory(records []GameRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(historyFilePath, data, 0644)
+}
+
+// 从当前棋盘开始记录新的一局
+func (g *Game) startRecord(practice bool) {
+	g.record = GameRecord{
+		StartedAt:    time.Now(),
+		Rules:        currentRules.name,
+		Practice:     practice,
+		InitialBoard: g.board.clone(),
+		InitialScore: g.score,
+	}
+}
+
+// 记录一步移动
+func (g *Game) recordMove(direction int, spawn *TileSpawn) {
+	g.record.Moves = append(g.record.Moves, MoveRecord{Direction: direction, Spawn: spawn})
+}
+
+// 撤销时删除最后一步记录
+func (g *Game) unrecordMove() {
+	if n := len(g.record.Moves); n > 0 {
+		g.record.Moves = g.record.Moves[:n-1]
+	}
+}
+
+// 当前对局的副本，填入截至目前的结果，供存档和赛后分析使用
+func (g *Game) resultRecord() GameRecord {
+	r := g.record
+	r.EndedAt = time.Now()
+	r.Score = g.score
+	r.MaxTile = g.board.maxTile()
+	r.Won = g.win
+	r.Milestone = g.milestone
+	r.Moves = append([]MoveRecord(nil), g.record.Moves...)
+	return r
+}
+
+// 将结束的一局写入历史记录
+//
+// 撤销后再次结束的同一局会替换原来的记录。
+func (g *Game) archiveGame() {
+	if !g.recording {
+		return
+	}
+
+	r := g.resultRecord()
+	replaced := false
+	for i := range g.history {
+		if g.history[i].StartedAt.Equal(r.StartedAt) {
+			g.history[i] = r
+			replaced = true
+		}
+	}
+	if !replaced {
+		g.history = append(g.history, r)
+	}
+	if len(g.history) > maxHistoryRecords {
+		g.history = g.history[len(g.history)-maxHistoryRecords:]
+	}
+
+	if err := saveHistory(g.history); err != nil {
+		log.Printf("保存历史记录失败: %v", err)
+	}
+}
+
+// 按分数从高到低排列的前 n 局，不包括练习局
+func topRecords(records []GameRecord, n int) []GameRecord {
+	var top []GameRecord
+	for _, r := range records {
+		if !r.Practice {
+			top = append(top, r)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
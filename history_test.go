@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestGameRecordValid(t *testing.T) {
+	board := Board{{2, 0, 0}, {0, 0, 0}, {0, 0, 2}}
+	tests := []struct {
+		name   string
+		record GameRecord
+		want   bool
+	}{
+		{
+			name: "正常记录",
+			record: GameRecord{InitialBoard: board, Moves: []MoveRecord{
+				{Direction: DirectionUp, Spawn: &TileSpawn{Row: 2, Col: 2, Value: 2}},
+				{Direction: DirectionLeft},
+			}},
+			want: true,
+		},
+		{
+			name:   "没有走法",
+			record: GameRecord{InitialBoard: board},
+			want:   true,
+		},
+		{
+			name:   "方向为负数",
+			record: GameRecord{InitialBoard: board, Moves: []MoveRecord{{Direction: -1}}},
+		},
+		{
+			name:   "方向超出范围",
+			record: GameRecord{InitialBoard: board, Moves: []MoveRecord{{Direction: DirectionUp}, {Direction: DirectionLeft + 1}}},
+		},
+		{
+			name:   "新方块位置超出棋盘",
+			record: GameRecord{InitialBoard: board, Moves: []MoveRecord{{Direction: DirectionUp, Spawn: &TileSpawn{Row: 3, Col: 0, Value: 2}}}},
+		},
+		{
+			name:   "新方块位置为负数",
+			record: GameRecord{InitialBoard: board, Moves: []MoveRecord{{Direction: DirectionUp, Spawn: &TileSpawn{Row: 0, Col: -1, Value: 2}}}},
+		},
+		{
+			name:   "棋盘不是正方形",
+			record: GameRecord{InitialBoard: Board{{2, 0, 0}, {0, 0}, {0, 0, 0}}},
+		},
+		{
+			name:   "棋盘尺寸过小",
+			record: GameRecord{InitialBoard: Board{{2, 0}, {0, 0}}},
+		},
+		{
+			name:   "没有棋盘",
+			record: GameRecord{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.record.valid(); got != tt.want {
+				t.Fatalf("valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// 内置语言包
+//
+//go:embed lang/*.json
+var builtinLangFS embed.FS
+
+// 支持的界面语言，第一个为默认语言
+var supportedLanguages = []string{"zh", "en"}
+
+// 启动时指定的界面语言，优先于配置文件和系统语言
+var langFlag = flag.String("lang", "", "界面语言，例如 zh 或 en")
+
+// 各语言的消息目录
+var catalogs = map[string]map[string]string{}
+
+// 当前界面语言
+var currentLanguage = supportedLanguages[0]
+
+// 加载所有内置语言包
+func loadCatalogs() {
+	for _, lang := range supportedLanguages {
+		data, err := builtinLangFS.ReadFile("lang/" + lang + ".json")
+		if err != nil {
+			log.Printf("读取语言包 %s 失败: %v", lang, err)
+			continue
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			log.Printf("解析语言包 %s 失败: %v", lang, err)
+			continue
+		}
+		catalogs[lang] = catalog
+	}
+}
+
+// 将语言或地区代码（如 zh_CN.UTF-8、en-US）规范为支持的语言，不支持时返回空字符串
+func matchLanguage(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		locale = locale[:i]
+	}
+	for _, lang := range supportedLanguages {
+		if lang == locale {
+			return lang
+		}
+	}
+	return ""
+}
+
+// 选择界面语言：命令行参数 > 配置文件 > 系统语言
+//
+// 系统语言不受支持时使用英文，无法检测系统语言时使用默认语言。
+func chooseLanguage(configured string) string {
+	for _, s := range []string{*langFlag, configured} {
+		if s == "" {
+			continue
+		}
+		if lang := matchLanguage(s); lang != "" {
+			return lang
+		}
+		log.Printf("不支持的界面语言: %s", s)
+	}
+
+	if locale := systemLocale(); locale != "" {
+		if lang := matchLanguage(locale); lang != "" {
+			return lang
+		}
+		return "en"
+	}
+	return supportedLanguages[0]
+}
+
+// 设置界面语言
+func setLanguage(lang string) {
+	if _, ok := catalogs[lang]; !ok {
+		log.Printf("缺少语言包: %s", lang)
+		lang = supportedLanguages[0]
+	}
+	currentLanguage = lang
+}
+
+// 查找翻译，当前语言缺少时依次使用默认语言和消息键本身
+func tr(key string, args ...interface{}) string {
+	s, ok := catalogs[currentLanguage][key]
+	if !ok {
+		s, ok = catalogs[supportedLanguages[0]][key]
+	}
+	if !ok {
+		s = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(s, args...)
+	}
+	return s
+}
+
+// 查找翻译，没有对应消息时返回 fallback
+func trOr(key, fallback string) string {
+	if s, ok := catalogs[currentLanguage][key]; ok {
+		return s
+	}
+	if s, ok := catalogs[supportedLanguages[0]][key]; ok {
+		return s
+	}
+	return fallback
+}
+
+// messageError 显示给玩家的错误，错误信息按当前语言翻译
+type messageError string
+
+func (e messageError) Error() string {
+	return tr(string(e))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 滑动手势阈值
+const (
+	swipeMinDistance = 50.0  // 慢速拖动需要达到的距离（像素）
+	flickMinDistance = 15.0  // 快速轻扫需要达到的最小距离（像素）
+	flickMinVelocity = 300.0 // 快速轻扫需要达到的速度（像素/秒）
+)
+
+// 按钮布局
+const (
+	buttonWidth   = 100
+	buttonHeight  = 20
+	buttonSpacing = 4
+	buttonTopY    = 90
+)
+
+// 按钮悬停时的高亮颜色
+var buttonHoverColor = color.RGBA{255, 255, 255, 60}
+
+// Button 屏幕上可点击的按钮
+type Button struct {
+	x, y, w, h int
+	label      string
+	action     Action
+}
+
+// 判断坐标是否在按钮内
+func (b *Button) contains(x, y int) bool {
+	return x >= b.x && x < b.x+b.w && y >= b.y && y < b.y+b.h
+}
+
+// 游戏界面上的按钮，两行两列排列；竖屏时在分数面板之间，横屏时在分数面板下方
+func gameButtons() []Button {
+	center := int(layout.infoX)
+	left := center - buttonWidth - buttonSpacing/2
+	right := center + buttonSpacing/2
+	top := layout.buttonsY
+	bottom := top + buttonHeight + buttonSpacing
+
+	return []Button{
+		{left, top, buttonWidth, buttonHeight, tr("button.new_game"), ActionReset},
+		{right, top, buttonWidth, buttonHeight, tr("button.undo"), ActionUndo},
+		{left, bottom, buttonWidth, buttonHeight, tr("button.save"), ActionSave},
+		{right, bottom, buttonWidth, buttonHeight, tr("button.load"), ActionLoad},
+	}
+}
+
+// 查找坐标处的按钮
+func buttonAt(x, y int) *Button {
+	buttons := gameButtons()
+	for i := range buttons {
+		if buttons[i].contains(x, y) {
+			return &buttons[i]
+		}
+	}
+	return nil
+}
+
+// 指针（鼠标拖动或触摸）的跟踪状态
+type pointerTracker struct {
+	active    bool
+	touch     bool
+	touchID   ebiten.TouchID
+	startX    int
+	startY    int
+	startTime time.Time
+}
+
+// 处理鼠标和触摸输入：按钮点击、滑动手势和点击继续
+func (g *Game) updatePointer() {
+	p := &g.pointer
+
+	// 开始跟踪新的按下
+	if !p.active {
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			x, y := cursorPosition()
+			g.pointerPressed(x, y, false, 0)
+		} else if ids := inpututil.AppendJustPressedTouchIDs(nil); len(ids) > 0 {
+			x, y := touchPosition(ids[0])
+			g.pointerPressed(x, y, true, ids[0])
+		}
+		return
+	}
+
+	// 检查是否松开
+	var endX, endY int
+	if p.touch {
+		if !inpututil.IsTouchJustReleased(p.touchID) {
+			return
+		}
+		endX, endY = layout.toLogical(inpututil.TouchPositionInPreviousTick(p.touchID))
+	} else {
+		if !inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+			return
+		}
+		endX, endY = cursorPosition()
+	}
+	p.active = false
+
+	dx := float64(endX - p.startX)
+	dy := float64(endY - p.startY)
+	if dir, ok := swipeDirection(dx, dy, time.Since(p.startTime)); ok {
+		g.performAction(dir)
+	}
+}
+
+// 处理指针按下：点中按钮直接执行，否则开始跟踪滑动
+func (g *Game) pointerPressed(x, y int, touch bool, id ebiten.TouchID) {
+	if b := buttonAt(x, y); b != nil {
+		g.performAction(b.action)
+		return
+	}
+
+	g.pointer = pointerTracker{
+		active:    true,
+		touch:     touch,
+		touchID:   id,
+		startX:    x,
+		startY:    y,
+		startTime: time.Now(),
+	}
+}
+
+// 根据位移和持续时间判断滑动方向
+func swipeDirection(dx, dy float64, elapsed time.Duration) (Action, bool) {
+	distance := math.Hypot(dx, dy)
+	velocity := distance / math.Max(elapsed.Seconds(), 0.001)
+
+	// 慢速拖动需要足够的距离，快速轻扫只需达到较小距离
+	if distance < swipeMinDistance && (distance < flickMinDistance || velocity < flickMinVelocity) {
+		return 0, false
+	}
+
+	if math.Abs(dx) > math.Abs(dy) {
+		if dx > 0 {
+			return ActionMoveRight, true
+		}
+		return ActionMoveLeft, true
+	}
+	if dy > 0 {
+		return ActionMoveDown, true
+	}
+	return ActionMoveUp, true
+}
+
+// 绘制游戏按钮
+func drawButtons(screen *ebiten.Image) {
+	cx, cy := cursorPosition()
+	for _, b := range gameButtons() {
+		drawRect(screen, float64(b.x), float64(b.y), float64(b.w), float64(b.h), currentTheme.Board)
+		if b.contains(cx, cy) {
+			drawRect(screen, float64(b.x), float64(b.y), float64(b.w), float64(b.h), buttonHoverColor)
+		}
+
+		labelWidth, labelHeight := textSize(scoreFont, b.label)
+		drawText(screen, b.label, scoreFont, float64(b.x)+(float64(b.w)-labelWidth)/2, float64(b.y)+(float64(b.h)+labelHeight)/2, currentTheme.TextLight)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Action 游戏中的输入动作，按键、手柄和按钮都映射到动作
+type Action int
+
+const (
+	ActionMoveUp Action = iota
+	ActionMoveRight
+	ActionMoveDown
+	ActionMoveLeft
+	ActionUndo
+	ActionReset
+	ActionContinue
+	ActionSave
+	ActionLoad
+	ActionEdit
+	ActionCopyPosition
+	ActionControls
+	ActionCycleTheme
+	ActionToggleMute
+	ActionVolumeUp
+	ActionVolumeDown
+	ActionSettings
+	ActionPause
+	ActionAnalyze
+	ActionTraining
+	actionCount
+)
+
+// 动作在配置文件中的名称
+var actionNames = [actionCount]string{
+	ActionMoveUp:       "move_up",
+	ActionMoveRight:    "move_right",
+	ActionMoveDown:     "move_down",
+	ActionMoveLeft:     "move_left",
+	ActionUndo:         "undo",
+	ActionReset:        "reset",
+	ActionContinue:     "continue",
+	ActionSave:         "save",
+	ActionLoad:         "load",
+	ActionEdit:         "edit",
+	ActionCopyPosition: "copy_position",
+	ActionControls:     "controls",
+	ActionCycleTheme:   "cycle_theme",
+	ActionToggleMute:   "toggle_mute",
+	ActionVolumeUp:     "volume_up",
+	ActionVolumeDown:   "volume_down",
+	ActionSettings:     "settings",
+	ActionPause:        "pause",
+	ActionAnalyze:      "analyze",
+	ActionTraining:     "training",
+}
+
+// 动作在按键设置界面中的显示名称
+func (a Action) label() string {
+	return tr("action." + actionNames[a])
+}
+
+// 根据配置名称查找动作
+func actionByName(name string) (Action, bool) {
+	for a := Action(0); a < actionCount; a++ {
+		if actionNames[a] == name {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// 移动动作对应的方向
+func (a Action) direction() (int, bool) {
+	switch a {
+	case ActionMoveUp:
+		return DirectionUp, true
+	case ActionMoveRight:
+		return DirectionRight, true
+	case ActionMoveDown:
+		return DirectionDown, true
+	case ActionMoveLeft:
+		return DirectionLeft, true
+	}
+	return 0, false
+}
+
+// KeyBindings 每个动作绑定的按键
+type KeyBindings [actionCount][]ebiten.Key
+
+// 判断动作的任一按键是否刚被按下
+func (kb *KeyBindings) justPressed(a Action) bool {
+	for _, k := range kb[a] {
+		if inpututil.IsKeyJustPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// 动作的按键名称，用于界面显示
+func (kb *KeyBindings) label(a Action) string {
+	names := make([]string, len(kb[a]))
+	for i, k := range kb[a] {
+		names[i] = k.String()
+	}
+	return strings.Join(names, " / ")
+}
+
+// 按键预设
+type keyPreset struct {
+	name     string
+	bindings KeyBindings
+}
+
+// 预设的显示名称
+func (p keyPreset) label() string {
+	return tr("preset." + p.name)
+}
+
+// 默认预设名称
+const defaultKeyPreset = "arrows"
+
+// 内置按键预设，WASD和HJKL预设保留方向键并调整冲突的按键
+var keyPresets = []keyPreset{
+	{
+		name: "arrows",
+		bindings: KeyBindings{
+			ActionMoveUp:       {ebiten.KeyArrowUp},
+			ActionMoveRight:    {ebiten.KeyArrowRight},
+			ActionMoveDown:     {ebiten.KeyArrowDown},
+			ActionMoveLeft:     {ebiten.KeyArrowLeft},
+			ActionUndo:         {ebiten.KeyU},
+			ActionReset:        {ebiten.KeyR},
+			ActionContinue:     {ebiten.KeySpace},
+			ActionSave:         {ebiten.KeyS},
+			ActionLoad:         {ebiten.KeyL},
+			ActionEdit:         {ebiten.KeyE},
+			ActionCopyPosition: {ebiten.KeyC},
+			ActionControls:     {ebiten.KeyF1},
+			ActionCycleTheme:   {ebiten.KeyT},
+			ActionToggleMute:   {ebiten.KeyM},
+			ActionVolumeUp:     {ebiten.KeyEqual},
+			ActionVolumeDown:   {ebiten.KeyMinus},
+			ActionSettings:     {ebiten.KeyF2},
+			ActionPause:        {ebiten.KeyEscape, ebiten.KeyP},
+			ActionAnalyze:      {ebiten.KeyF3},
+			ActionTraining:     {ebiten.KeyF4},
+		},
+	},
+	{
+		name: "wasd",
+		bindings: KeyBindings{
+			ActionMoveUp:       {ebiten.KeyW, ebiten.KeyArrowUp},
+			ActionMoveRight:    {ebiten.KeyD, ebiten.KeyArrowRight},
+			ActionMoveDown:     {ebiten.KeyS, ebiten.KeyArrowDown},
+			ActionMoveLeft:     {ebiten.KeyA, ebiten.KeyArrowLeft},
+			ActionUndo:         {ebiten.KeyU},
+			ActionReset:        {ebiten.KeyR},
+			ActionContinue:     {ebiten.KeySpace},
+			ActionSave:         {ebiten.KeyF5},
+			ActionLoad:         {ebiten.KeyF9},
+			ActionEdit:         {ebiten.KeyE},
+			ActionCopyPosition: {ebiten.KeyC},
+			ActionControls:     {ebiten.KeyF1},
+			ActionCycleTheme:   {ebiten.KeyT},
+			ActionToggleMute:   {ebiten.KeyM},
+			ActionVolumeUp:     {ebiten.KeyEqual},
+			ActionVolumeDown:   {ebiten.KeyMinus},
+			ActionSettings:     {ebiten.KeyF2},
+			ActionPause:        {ebiten.KeyEscape, ebiten.KeyP},
+			ActionAnalyze:      {ebiten.KeyF3},
+			ActionTraining:     {ebiten.KeyF4},
+		},
+	},
+	{
+		name: "hjkl",
+		bindings: KeyBindings{
+			ActionMoveUp:       {ebiten.KeyK, ebiten.KeyArrowUp},
+			ActionMoveRight:    {ebiten.KeyL, ebiten.KeyArrowRight},
+			ActionMoveDown:     {ebiten.KeyJ, ebiten.KeyArrowDown},
+			ActionMoveLeft:     {ebiten.KeyH, ebiten.KeyArrowLeft},
+			ActionUndo:         {ebiten.KeyU},
+			ActionReset:        {ebiten.KeyR},
+			ActionContinue:     {ebiten.KeySpace},
+			ActionSave:         {ebiten.KeyS},
+			ActionLoad:         {ebiten.KeyF9},
+			ActionEdit:         {ebiten.KeyE},
+			ActionCopyPosition: {ebiten.KeyC},
+			ActionControls:     {ebiten.KeyF1},
+			ActionCycleTheme:   {ebiten.KeyT},
+			ActionToggleMute:   {ebiten.KeyM},
+			ActionVolumeUp:     {ebiten.KeyEqual},
+			ActionVolumeDown:   {ebiten.KeyMinus},
+			ActionSettings:     {ebiten.KeyF2},
+			ActionPause:        {ebiten.KeyEscape, ebiten.KeyP},
+			ActionAnalyze:      {ebiten.KeyF3},
+			ActionTraining:     {ebiten.KeyF4},
+		},
+	},
+}
+
+// 根据名称查找按键预设，找不到时返回默认预设
+func findKeyPreset(name string) keyPreset {
+	for _, p := range keyPresets {
+		if p.name == name {
+			return p
+		}
+	}
+	return keyPresets[0]
+}
+
+// 以预设为基础，叠加配置文件中的自定义按键
+func resolveKeyBindings(preset string, overrides map[string][]string) KeyBindings {
+	bindings := findKeyPreset(preset).bindings
+	for a := range bindings {
+		bindings[a] = append([]ebiten.Key(nil), bindings[a]...)
+	}
+
+	for name, keyNames := range overrides {
+		a, ok := actionByName(name)
+		if !ok {
+			log.Printf("未知的按键动作: %s", name)
+			continue
+		}
+
+		keys := make([]ebiten.Key, 0, len(keyNames))
+		for _, keyName := range keyNames {
+			var k ebiten.Key
+			if err := k.UnmarshalText([]byte(keyName)); err != nil {
+				log.Printf("未知的按键名称: %s", keyName)
+				continue
+			}
+			keys = append(keys, k)
+		}
+		bindings[a] = keys
+	}
+
+	return bindings
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
+)
+
+// 横屏画布尺寸（逻辑单位），竖屏画布沿用 screenWidth x screenHeight
+const (
+	landscapeWidth  = 780
+	landscapeHeight = 475
+)
+
+// 窗口宽高比超过该值时使用横屏布局，信息面板放在棋盘右侧
+const landscapeAspect = 1.2
+
+// 棋盘格子区域的边长（逻辑单位），按 4x4 棋盘计算，其他尺寸在此区域内缩放方块
+const boardPixels = defaultTileSize*defaultBoardSize + tileMargin*(defaultBoardSize-1)
+
+// screenLayout 根据窗口尺寸计算的界面布局
+//
+// 绘制代码使用逻辑坐标，布局负责把逻辑坐标缩放并居中到实际画面上。
+type screenLayout struct {
+	width, height int     // 实际画面尺寸（设备像素）
+	scale         float64 // 逻辑单位到设备像素的缩放
+	offsetX       float64 // 画布居中后的偏移（设备像素）
+	offsetY       float64
+	landscape     bool
+
+	// 以下均为逻辑坐标
+	canvasWidth  float64
+	canvasHeight float64
+	boardX       int // 棋盘格子区域左上角
+	boardY       int
+	infoX        float64 // 标题、分数、按钮所在列的中心
+	titleY       int
+	panelLeftX   float64 // 分数面板
+	panelRightX  float64
+	panelY       int
+	buttonsY     int
+	hintY        int
+}
+
+// 当前布局
+var layout = newScreenLayout(screenWidth, screenHeight)
+
+// 根据画面尺寸计算布局
+func newScreenLayout(width, height int) screenLayout {
+	l := screenLayout{width: width, height: height}
+	l.landscape = float64(width) > float64(height)*landscapeAspect
+
+	if l.landscape {
+		// 横屏：棋盘在左，信息面板在右
+		l.canvasWidth, l.canvasHeight = landscapeWidth, landscapeHeight
+		l.boardX, l.boardY = 30, 30
+		right := float64(l.boardX + boardPixels + boardMargin)
+		l.infoX = right + (landscapeWidth-right)/2
+		l.titleY = 90
+		l.panelLeftX = l.infoX - scorePanelWidth - 5
+		l.panelRightX = l.infoX + 5
+		l.panelY = 120
+		l.buttonsY = 200
+		l.hintY = 270
+	} else {
+		// 竖屏：信息面板在棋盘上方
+		l.canvasWidth, l.canvasHeight = screenWidth, screenHeight
+		l.boardX, l.boardY = (screenWidth-boardPixels)/2, 180
+		l.infoX = screenWidth / 2
+		l.titleY = 60
+		l.panelLeftX = (screenWidth/2-scorePanelWidth)/2 - 45
+		l.panelRightX = screenWidth/2 + (screenWidth/2-scorePanelWidth)/2 + 45
+		l.panelY = 90
+		l.buttonsY = buttonTopY
+		l.hintY = 150
+	}
+
+	l.scale = math.Min(float64(width)/l.canvasWidth, float64(height)/l.canvasHeight)
+	if l.scale <= 0 {
+		l.scale = 1
+	}
+	l.offsetX = (float64(width) - l.canvasWidth*l.scale) / 2
+	l.offsetY = (float64(height) - l.canvasHeight*l.scale) / 2
+	return l
+}
+
+// 逻辑坐标转换为设备像素
+func (l *screenLayout) toScreen(x, y float64) (float64, float64) {
+	return l.offsetX + x*l.scale, l.offsetY + y*l.scale
+}
+
+// 设备像素转换为逻辑坐标
+func (l *screenLayout) toLogical(x, y int) (int, int) {
+	lx := (float64(x) - l.offsetX) / l.scale
+	ly := (float64(y) - l.offsetY) / l.scale
+	return int(math.Floor(lx)), int(math.Floor(ly))
+}
+
+// 鼠标位置（逻辑坐标）
+func cursorPosition() (int, int) {
+	return layout.toLogical(ebiten.CursorPosition())
+}
+
+// 触摸位置（逻辑坐标）
+func touchPosition(id ebiten.TouchID) (int, int) {
+	return layout.toLogical(ebiten.TouchPosition(id))
+}
+
+// 以逻辑坐标绘制矩形
+func drawRect(screen *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	sx, sy := layout.toScreen(x, y)
+	ebitenutil.DrawRect(screen, sx, sy, w*layout.scale, h*layout.scale, clr)
+}
+
+// 以逻辑坐标绘制线段，width 为逻辑线宽
+func drawLine(screen *ebiten.Image, x0, y0, x1, y1, width float64, clr color.Color) {
+	sx0, sy0 := layout.toScreen(x0, y0)
+	sx1, sy1 := layout.toScreen(x1, y1)
+	vector.StrokeLine(screen, float32(sx0), float32(sy0), float32(sx1), float32(sy1), float32(width*layout.scale), clr, true)
+}
+
+// 以逻辑坐标绘制实心圆
+func drawCircle(screen *ebiten.Image, x, y, r float64, clr color.Color) {
+	sx, sy := layout.toScreen(x, y)
+	vector.DrawFilledCircle(screen, float32(sx), float32(sy), float32(r*layout.scale), clr, true)
+}
+
+// 以逻辑坐标绘制文本，y 为基线位置；字体已按布局缩放
+func drawText(screen *ebiten.Image, s string, face font.Face, x, y float64, clr color.Color) {
+	sx, sy := layout.toScreen(x, y)
+	text.Draw(screen, s, face, int(math.Round(sx)), int(math.Round(sy)), clr)
+}
+
+// 文本尺寸（逻辑单位）
+func textSize(face font.Face, s string) (float64, float64) {
+	bounds, _ := font.BoundString(face, s)
+	w := float64((bounds.Max.X - bounds.Min.X).Ceil())
+	h := float64((bounds.Max.Y - bounds.Min.Y).Ceil())
+	return w / layout.scale, h / layout.scale
+}
+
+// 以 x 为中心水平居中绘制文本
+func drawTextCentered(screen *ebiten.Image, s string, face font.Face, x, y float64, clr color.Color) {
+	w, _ := textSize(face, s)
+	drawText(screen, s, face, x-w/2, y, clr)
+}
+
+// 说明文字的分行：竖屏一行显示，横屏按 " | " 拆分成多行放入侧边面板
+func (l *screenLayout) hintLines(s string) []string {
+	if !l.landscape {
+		return []string{s}
+	}
+	return strings.Split(s, " | ")
+}
+
+// 使用整个画面绘制半透明遮罩
+func drawScreenShade(screen *ebiten.Image, clr color.Color) {
+	ebitenutil.DrawRect(screen, 0, 0, float64(layout.width), float64(layout.height), clr)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 排行榜布局
+const (
+	leaderboardSize      = 10  // 显示的对局数
+	leaderboardListTop   = 100 // 列表的起始纵坐标
+	leaderboardRowHeight = 32
+)
+
+// 排行榜场景：历史最高分的对局，选中后可以回放
+type leaderboardScene struct {
+	records  []GameRecord
+	selected int
+}
+
+// 打开排行榜
+func (g *Game) openLeaderboard() {
+	g.pushScene(&leaderboardScene{records: topRecords(g.history, leaderboardSize)})
+}
+
+// 坐标处的行，没有时返回 -1
+func (s *leaderboardScene) rowAt(y int) int {
+	if y < leaderboardListTop {
+		return -1
+	}
+	row := (y - leaderboardListTop) / leaderboardRowHeight
+	if row >= len(s.records) {
+		return -1
+	}
+	return row
+}
+
+func (s *leaderboardScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.popScene()
+		return
+	}
+
+	n := len(s.records)
+	if n == 0 {
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			g.popScene()
+		}
+		return
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp), g.keys.justPressed(ActionMoveUp):
+		s.selected = stepIndex(s.selected, -1, n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown), g.keys.justPressed(ActionMoveDown):
+		s.selected = stepIndex(s.selected, 1, n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		g.openReplay(s.records[s.selected])
+		return
+	case g.keys.justPressed(ActionAnalyze):
+		g.openAnalysis(s.records[s.selected])
+		return
+	}
+
+	// 点击选中，再次点击回放
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		_, y := cursorPosition()
+		if row := s.rowAt(y); row >= 0 {
+			if row == s.selected {
+				g.openReplay(s.records[row])
+			} else {
+				s.selected = row
+			}
+		}
+	}
+}
+
+func (s *leaderboardScene) draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	drawTextCentered(screen, tr("leaderboard.title"), titleFont, centerX, 60, currentTheme.Text)
+
+	if len(s.records) == 0 {
+		drawTextCentered(screen, tr("leaderboard.empty"), boldFont, centerX, layout.canvasHeight/2, currentTheme.Text)
+		return
+	}
+
+	for i, r := range s.records {
+		y := float64(leaderboardListTop + i*leaderboardRowHeight)
+
+		col := currentTheme.Text
+		if i == s.selected {
+			drawRect(screen, boardMargin, y, layout.canvasWidth-2*boardMargin, leaderboardRowHeight-4, currentTheme.Board)
+			col = currentTheme.TextLight
+		}
+
+		baseline := y + leaderboardRowHeight/2 + 4
+		size := len(r.InitialBoard)
+		left := fmt.Sprintf("%d.  %d", i+1, r.Score)
+		right := fmt.Sprintf("%d  %dx%d  %s", r.MaxTile, size, size, r.StartedAt.Format("2006-01-02"))
+		drawText(screen, left, boldFont, boardMargin+10, baseline, col)
+		rightWidth, _ := textSize(scoreFont, right)
+		drawText(screen, right, scoreFont, layout.canvasWidth-boardMargin-10-rightWidth, baseline, col)
+	}
+
+	drawTextCentered(screen, tr("leaderboard.help", g.keyHint(ActionAnalyze)), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+}
+
+func (s *leaderboardScene) overlay() bool { return false }
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 系统语言，例如 zh_CN.UTF-8，无法获取时返回空字符串
+func systemLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+
+	// 从 Finder 启动的 macOS 程序没有 LANG 环境变量
+	if runtime.GOOS == "darwin" {
+		out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// 系统语言，例如 zh-CN，无法获取时返回空字符串
+func systemLocale() string {
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("GetUserDefaultLocaleName")
+	if proc.Find() != nil {
+		return ""
+	}
+
+	buf := make([]uint16, 85) // LOCALE_NAME_MAX_LENGTH
+	n, _, _ := proc.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if n == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
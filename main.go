@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"image/color"
 	"io/ioutil"
@@ -12,48 +13,20 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 )
 
 const (
 	screenWidth  = 450
 	screenHeight = 600
-	boardSize    = 4
-	tileSize     = 100
 	tileMargin   = 5
 	boardMargin  = 20
 )
 
-// 游戏颜色
-var (
-	backgroundColor = color.RGBA{250, 248, 239, 255}
-	boardColor      = color.RGBA{187, 173, 160, 255}
-	emptyTileColor  = color.RGBA{205, 193, 180, 255}
-	textColor       = color.RGBA{119, 110, 101, 255}
-	textColorLight  = color.RGBA{249, 246, 242, 255}
-
-	// 不同数值对应的颜色
-	tileColors = map[int]color.RGBA{
-		0:    {205, 193, 180, 255}, // 空白格
-		2:    {238, 228, 218, 255},
-		4:    {237, 224, 200, 255},
-		8:    {242, 177, 121, 255},
-		16:   {245, 149, 99, 255},
-		32:   {246, 124, 95, 255},
-		64:   {246, 94, 59, 255},
-		128:  {237, 207, 114, 255},
-		256:  {237, 204, 97, 255},
-		512:  {237, 200, 80, 255},
-		1024: {237, 197, 63, 255},
-		2048: {237, 194, 46, 255},
-		4096: {94, 218, 146, 255},
-		8192: {57, 188, 120, 255},
-	}
+// 分数面板尺寸
+const (
+	scorePanelWidth  = 100
+	scorePanelHeight = 60
 )
 
 // 字体
@@ -62,16 +35,13 @@ var (
 	boldFont   font.Face
 	titleFont  font.Face
 	scoreFont  font.Face
-	chineseFont font.Face
 )
 
-// 移动动画的持续时间
-const animationDuration = 10
-
 // 方块动画类型
 const (
 	AnimationMove = iota // 移动动画
 	AnimationMerge       // 合并动画
+	AnimationSpawn       // 新方块出现动画
 )
 
 // 方块动画状态
@@ -83,49 +53,106 @@ type TileAnimation struct {
 	animType     int   // 动画类型
 }
 
+// 最多可撤销的步数
+const maxUndoSteps = 50
+
+// 撤销用的游戏快照
+type gameSnapshot struct {
+	board      Board
+	score      int
+	win        bool
+	showWin    bool
+	milestone  int
+	nextSpawn  *TileSpawn
+	leftCorner bool // 最大方块是否曾经离开角落，撤销后恢复
+}
+
 // 游戏进度文件路径
 const saveFilePath = "2048_save.json"
 
 // GameSave 用于保存游戏状态
 type GameSave struct {
-	Board     [boardSize][boardSize]int `json:"board"`
-	Score     int                       `json:"score"`
-	BestScore int                       `json:"best_score"`
-	GameOver  bool                      `json:"game_over"`
-	Win       bool                      `json:"win"`
-	ShowWin   bool                      `json:"show_win"`
+	Board     Board       `json:"board"`
+	Score     int         `json:"score"`
+	BestScore int         `json:"best_score"`
+	GameOver  bool        `json:"game_over"`
+	Win       bool        `json:"win"`
+	ShowWin   bool        `json:"show_win"`
+	Milestone int         `json:"milestone,omitempty"`
+	NextSpawn *TileSpawn  `json:"next_spawn,omitempty"`
+	Record    *GameRecord `json:"record,omitempty"` // 当前对局的记录，用于回放
 }
 
 // Game 代表游戏状态
 type Game struct {
-	board             [boardSize][boardSize]int
-	previousBoard     [boardSize][boardSize]int // 用于保存移动前的棋盘状态
+	board             Board
+	previousBoard     Board // 用于保存移动前的棋盘状态
 	score             int
 	bestScore         int
 	gameOver          bool
 	win               bool
-	showWin           bool
+	showWin           bool // 是否显示当前里程碑的庆祝界面
+	milestone         int  // 本局达到的最高里程碑
 	message           string
 	messageTime       int
-	animating         bool         // 是否正在执行动画
-	animationProgress float64      // 动画进度 (0.0 - 1.0)
-	animations        []TileAnimation // 方块动画列表
-	lastMoveDirection int          // 最后一次移动的方向
+	animating         bool                               // 是否正在执行动画
+	animationElapsed  time.Duration                      // 当前动画已播放的时间
+	lastUpdate        time.Time                          // 上一次更新的时间，用于计算帧间隔
+	animations        []TileAnimation                    // 方块动画列表
+	lastMoveDirection int                                // 最后一次移动的方向
+	nextSpawn         *TileSpawn                         // 指定的下一个生成方块（来自编辑器）
+	editor            Editor                             // 棋盘编辑器
+	undoStack         []gameSnapshot                     // 撤销历史
+	pointer           pointerTracker                     // 鼠标和触摸手势跟踪
+	gamepads          map[ebiten.GamepadID]*gamepadState // 已连接的手柄
+	padActions        []Action                           // 本帧手柄触发的动作
+	config            Config                             // 用户配置
+	keys              KeyBindings                        // 当前按键绑定
+	controls          controlsScreen                     // 按键设置界面
+	settings          settingsScreen                     // 设置界面
+	moveQueue         []int                              // 动画期间缓冲的移动方向
+	themes            []*Theme                           // 可用的主题
+	audio             *audioSystem                       // 音效和背景音乐
+	scenes            []scene                            // 场景栈，栈顶场景处理输入
+	transition        int                                // 场景切换淡入的剩余帧数
+	quitRequested     bool                               // 从菜单退出游戏
+	recording         bool                               // 是否记录对局，回放用的棋盘不记录
+	record            GameRecord                         // 当前对局的记录
+	history           []GameRecord                       // 已结束的对局
+	focused           bool                               // 上一帧窗口是否有焦点
+	merges            []int                              // 本次移动合并出的方块数值
+	achievements      map[string]time.Time               // 已解锁的成就和解锁时间
+	toasts            []string                           // 等待显示的成就提示
+	toastTime         int                                // 当前成就提示剩余的帧数
+	training          *trainingInfo                      // 缓存的训练提示
+	pendingMove       *moveEvent                         // 等待动画结束后结算的移动
 }
 
 // 初始化游戏
 func NewGame() *Game {
 	g := &Game{
-		score:            0,
-		bestScore:        0,
-		gameOver:         false,
-		win:              false,
-		showWin:          true,
-		animating:        false,
-		animationProgress: 0,
-		animations:       []TileAnimation{},
+		score:             0,
+		bestScore:         0,
+		gameOver:          false,
+		win:               false,
+		showWin:           true,
+		animating:         false,
+		animations:        []TileAnimation{},
 		lastMoveDirection: -1,
+		config:            loadConfig(),
+		recording:         true,
+		focused:           true,
+		scenes:            []scene{playScene{}},
 	}
+	setLanguage(chooseLanguage(g.config.Language))
+	g.keys = resolveKeyBindings(g.config.KeyPreset, g.config.KeyBindings)
+	g.themes = loadThemes()
+	g.selectTheme(g.config.Theme)
+	setTileNotation(g.config.TileNotation)
+	g.audio = newAudioSystem()
+	g.applyVolume()
+	g.history = loadHistory()
+	g.achievements = loadAchievements()
 	
 	// 尝试加载存档
 	if !g.loadGame() {
@@ -136,18 +163,20 @@ func NewGame() *Game {
 	return g
 }
 
-// 初始化棋盘
+// 初始化棋盘，新游戏使用配置的棋盘尺寸
 func (g *Game) initBoard() {
+	// 新游戏使用配置的规则
+	currentRules = findRuleVariant(g.config.RuleVariant)
+
 	// 清空棋盘
-	for i := 0; i < boardSize; i++ {
-		for j := 0; j < boardSize; j++ {
-			g.board[i][j] = 0
-		}
-	}
+	setBoardSize(g.config.BoardSize)
+	g.board = newBoard(boardSize)
+	g.previousBoard = newBoard(boardSize)
 
 	// 添加两个初始方块
 	g.addRandomTile()
 	g.addRandomTile()
+	g.startRecord(false)
 }
 
 // 重置游戏
@@ -156,14 +185,100 @@ func (g *Game) resetGame() {
 	g.gameOver = false
 	g.win = false
 	g.showWin = true
+	g.milestone = 0
+	g.nextSpawn = nil
+	g.undoStack = nil
+	g.moveQueue = nil
+	g.pendingMove = nil
+	g.animating = false
+	g.animations = []TileAnimation{}
 	g.initBoard()
 	
 	// 删除存档文件
 	g.deleteSave()
 }
 
-// 添加随机方块
-func (g *Game) addRandomTile() {
+// 从指定局面开始游戏
+func (g *Game) setPosition(pos Position) {
+	currentRules = findRuleVariant(g.config.RuleVariant)
+	setBoardSize(len(pos.Board))
+	g.board = pos.Board.clone()
+	g.previousBoard = newBoard(boardSize)
+	g.score = pos.Score
+	if g.score > g.bestScore {
+		g.bestScore = g.score
+	}
+	g.nextSpawn = pos.NextSpawn
+	g.gameOver = false
+	g.win = false
+	g.showWin = true
+	g.milestone = 0
+	g.animating = false
+	g.animations = []TileAnimation{}
+	g.undoStack = nil
+	g.moveQueue = nil
+	g.pendingMove = nil
+
+	// 已经达到里程碑的练习局面不再弹出庆祝界面
+	g.checkWin()
+	if g.win {
+		g.showWin = false
+	}
+
+	// 轮到生成方块时先补上新方块
+	if pos.Side == SideSpawn {
+		g.addRandomTile()
+		if !g.canMove() {
+			g.gameOver = true
+		}
+	}
+	g.startRecord(true)
+}
+
+// 当前局面
+func (g *Game) position() Position {
+	return Position{
+		Board:     g.board.clone(),
+		Side:      SidePlayer,
+		Score:     g.score,
+		NextSpawn: g.nextSpawn,
+	}
+}
+
+// 四个方向中哪些会改变当前棋盘，按方向常量的顺序排列
+func (g *Game) legalMoves() [4]bool {
+	return g.board.legalMoves()
+}
+
+// 是否有未结束的对局可以继续
+func (g *Game) hasProgress() bool {
+	return !g.gameOver && (g.score > 0 || len(g.record.Moves) > 0)
+}
+
+// 将当前局面的记谱复制到剪贴板
+func (g *Game) copyPosition() {
+	notation := formatPosition(g.position())
+	log.Printf("当前局面: %s", notation)
+
+	if err := writeClipboard(notation); err != nil {
+		log.Printf("复制到剪贴板失败: %v", err)
+		g.showMessage(tr("msg.copy_position_failed"), 60)
+		return
+	}
+	g.showMessage(tr("msg.position_copied"), 60)
+}
+
+// 添加随机方块，返回新方块的位置和数值
+func (g *Game) addRandomTile() (TileSpawn, bool) {
+	// 优先使用编辑器指定的生成方块
+	if spawn := g.nextSpawn; spawn != nil {
+		g.nextSpawn = nil
+		if spawn.Row < boardSize && spawn.Col < boardSize && g.board[spawn.Row][spawn.Col] == 0 {
+			g.board[spawn.Row][spawn.Col] = spawn.Value
+			return *spawn, true
+		}
+	}
+
 	// 找出所有空白格
 	var emptyCells [][2]int
 	for i := 0; i < boardSize; i++ {
@@ -176,58 +291,34 @@ func (g *Game) addRandomTile() {
 
 	// 如果没有空白格，返回
 	if len(emptyCells) == 0 {
-		return
+		return TileSpawn{}, false
 	}
 
 	// 随机选择一个空白格
 	cell := emptyCells[rand.Intn(len(emptyCells))]
 	i, j := cell[0], cell[1]
 
-	// 90%的概率生成2，10%的概率生成4
-	if rand.Float64() < 0.9 {
-		g.board[i][j] = 2
-	} else {
+	// 按规则的概率生成2或4
+	if rand.Float64() < currentRules.fourChance {
 		g.board[i][j] = 4
+	} else {
+		g.board[i][j] = 2
 	}
+
+	return TileSpawn{Row: i, Col: j, Value: g.board[i][j]}, true
 }
 
 // 检查是否可以移动
 func (g *Game) canMove() bool {
-	// 检查是否有空白格
-	for i := 0; i < boardSize; i++ {
-		for j := 0; j < boardSize; j++ {
-			if g.board[i][j] == 0 {
-				return true
-			}
-		}
-	}
-
-	// 检查是否有相邻的相同数字
-	for i := 0; i < boardSize; i++ {
-		for j := 0; j < boardSize; j++ {
-			// 检查右边
-			if j < boardSize-1 && g.board[i][j] == g.board[i][j+1] {
-				return true
-			}
-			// 检查下边
-			if i < boardSize-1 && g.board[i][j] == g.board[i+1][j] {
-				return true
-			}
-		}
-	}
-
-	return false
+	return g.board.canMove()
 }
 
-// 检查是否胜利
+// 检查是否达到新的里程碑：达到胜利目标即胜利，之后每次翻倍再庆祝一次；无尽规则没有胜利目标
 func (g *Game) checkWin() {
-	for i := 0; i < boardSize; i++ {
-		for j := 0; j < boardSize; j++ {
-			if g.board[i][j] == 2048 {
-				g.win = true
-				return
-			}
-		}
+	if m := currentRules.milestone(g.board.maxTile()); m > g.milestone {
+		g.milestone = m
+		g.win = true
+		g.showWin = true
 	}
 }
 
@@ -239,6 +330,11 @@ const (
 	DirectionLeft
 )
 
+// 是否为四个方向之一，来自存档和历史记录的方向需要先检查
+func validDirection(direction int) bool {
+	return direction >= DirectionUp && direction <= DirectionLeft
+}
+
 // 移动方块
 func (g *Game) move(direction int) bool {
 	// 如果正在动画中，不处理输入
@@ -246,9 +342,17 @@ func (g *Game) move(direction int) bool {
 		return false
 	}
 
+	// 不会改变棋盘的方向不算一步，不记录快照和方向
+	if !validDirection(direction) || !g.legalMoves()[direction] {
+		return false
+	}
+
 	// 保存最后一次移动方向
 	g.lastMoveDirection = direction
 
+	// 记录移动前的快照用于撤销
+	snapshot := g.snapshot()
+
 	// 保存移动前的棋盘状态用于动画
 	for i := 0; i < boardSize; i++ {
 		for j := 0; j < boardSize; j++ {
@@ -257,6 +361,7 @@ func (g *Game) move(direction int) bool {
 	}
 	
 	moved := false
+	g.merges = nil
 	
 	// 根据方向进行移动
 	switch direction {
@@ -272,24 +377,156 @@ func (g *Game) move(direction int) bool {
 
 	// 如果有移动，添加一个随机方块并准备动画
 	if moved {
+		g.pushUndo(snapshot)
+
 		// 为移动的方块创建动画
 		g.prepareAnimations()
+
+		// 添加随机方块，并在滑动结束后播放出现动画
+		var recorded *TileSpawn
+		if spawn, ok := g.addRandomTile(); ok {
+			g.animations = append(g.animations, TileAnimation{
+				fromX:    spawn.Col,
+				fromY:    spawn.Row,
+				toX:      spawn.Col,
+				toY:      spawn.Row,
+				value:    spawn.Value,
+				animType: AnimationSpawn,
+			})
+			recorded = &spawn
+		}
+		g.recordMove(direction, recorded)
+		g.playMoveSounds()
 		
-		// 开始动画
-		g.animating = true
-		g.animationProgress = 0
+		// 开始动画，关闭动画时直接显示结果
+		g.animating = g.config.AnimationsEnabled
+		g.animationElapsed = 0
 		
+		wasWin, wasMilestone := g.win, g.milestone
 		g.checkWin()
-		
-		// 检查游戏是否结束
-		if !g.canMove() {
-			g.gameOver = true
+		milestone := 0
+		if g.milestone > wasMilestone {
+			milestone = g.milestone
+		}
+
+		// 胜利和结束等到动画播放完再结算，关闭动画时立即结算
+		g.pendingMove = &moveEvent{
+			direction: direction,
+			merges:    g.merges,
+			gained:    g.score - snapshot.score,
+			moves:     len(g.record.Moves),
+			maxTile:   g.board.maxTile(),
+			won:       g.win && !wasWin,
+			milestone: milestone,
+			gameOver:  !g.canMove(),
+		}
+		if !g.animating {
+			g.settleMove()
 		}
 	}
 
 	return moved
 }
 
+// 结算动画结束的移动：胜利和结束的音效、结束时存入历史记录，并通知成就等移动事件的监听者
+func (g *Game) settleMove() {
+	e := g.pendingMove
+	if e == nil {
+		return
+	}
+	g.pendingMove = nil
+
+	// 接下来要显示庆祝或结束界面，丢弃动画期间缓冲的移动
+	if e.milestone > 0 || e.gameOver {
+		g.moveQueue = nil
+	}
+
+	if e.milestone > 0 {
+		g.playSound(SoundWin, 0)
+	}
+	if e.gameOver {
+		g.gameOver = true
+		g.playSound(SoundGameOver, 0)
+		g.archiveGame()
+		if g.recording {
+			g.saveGame(false)
+		}
+	}
+	g.dispatchMoveEvent(*e)
+}
+
+// 播放移动的音效：滑动、最大的一次合并和新方块出现
+func (g *Game) playMoveSounds() {
+	g.playSound(SoundSlide, 0)
+
+	merged := 0
+	for _, anim := range g.animations {
+		if anim.animType == AnimationMerge && anim.value*2 > merged {
+			merged = anim.value * 2
+		}
+	}
+	if merged > 0 {
+		g.playSound(SoundMerge, merged)
+	}
+
+	for _, anim := range g.animations {
+		if anim.animType == AnimationSpawn {
+			g.playSound(SoundSpawn, 0)
+			break
+		}
+	}
+}
+
+// 获取当前游戏快照
+func (g *Game) snapshot() gameSnapshot {
+	return gameSnapshot{
+		board:      g.board.clone(),
+		score:      g.score,
+		win:        g.win,
+		showWin:    g.showWin,
+		milestone:  g.milestone,
+		nextSpawn:  g.nextSpawn,
+		leftCorner: g.record.LeftCorner,
+	}
+}
+
+// 记录一步撤销历史，超出上限时丢弃最早的记录
+func (g *Game) pushUndo(s gameSnapshot) {
+	g.undoStack = append(g.undoStack, s)
+	if len(g.undoStack) > maxUndoSteps {
+		g.undoStack = g.undoStack[len(g.undoStack)-maxUndoSteps:]
+	}
+}
+
+// 撤销上一步移动
+func (g *Game) undo() {
+	if len(g.undoStack) == 0 {
+		g.showMessage(tr("msg.cannot_undo"), 60)
+		return
+	}
+
+	s := g.undoStack[len(g.undoStack)-1]
+	g.undoStack = g.undoStack[:len(g.undoStack)-1]
+
+	g.board = s.board
+	g.score = s.score
+	g.win = s.win
+	g.showWin = s.showWin
+	g.milestone = s.milestone
+	g.nextSpawn = s.nextSpawn
+	g.unrecordMove()
+	g.record.LeftCorner = s.leftCorner
+	g.record.Undos++
+	g.gameOver = false
+	g.animating = false
+	g.animations = []TileAnimation{}
+	g.moveQueue = nil
+	g.pendingMove = nil
+
+	g.saveGame(false)
+	g.showMessage(tr("msg.undone"), 60)
+}
+
 // 准备方块移动动画
 func (g *Game) prepareAnimations() {
 	g.animations = []TileAnimation{}
@@ -437,9 +674,6 @@ func (g *Game) prepareAnimations() {
 			}
 		}
 	}
-	
-	// 添加随机生成的方块
-	g.addRandomTile()
 }
 
 // 向上移动
@@ -462,6 +696,7 @@ func (g *Game) moveUp() bool {
 				} else if g.board[i][j] == g.board[k][j] {
 					g.board[i][j] *= 2
 					g.score += g.board[i][j]
+					g.merges = append(g.merges, g.board[i][j])
 					if g.score > g.bestScore {
 						g.bestScore = g.score
 					}
@@ -512,6 +747,7 @@ func (g *Game) moveRight() bool {
 				} else if g.board[i][j] == g.board[i][k] {
 					g.board[i][j] *= 2
 					g.score += g.board[i][j]
+					g.merges = append(g.merges, g.board[i][j])
 					if g.score > g.bestScore {
 						g.bestScore = g.score
 					}
@@ -562,6 +798,7 @@ func (g *Game) moveDown() bool {
 				} else if g.board[i][j] == g.board[k][j] {
 					g.board[i][j] *= 2
 					g.score += g.board[i][j]
+					g.merges = append(g.merges, g.board[i][j])
 					if g.score > g.bestScore {
 						g.bestScore = g.score
 					}
@@ -612,6 +849,7 @@ func (g *Game) moveLeft() bool {
 				} else if g.board[i][j] == g.board[i][k] {
 					g.board[i][j] *= 2
 					g.score += g.board[i][j]
+					g.merges = append(g.merges, g.board[i][j])
 					if g.score > g.bestScore {
 						g.bestScore = g.score
 					}
@@ -652,6 +890,9 @@ func (g *Game) saveGame(showMessage bool) {
 		GameOver:  g.gameOver,
 		Win:       g.win,
 		ShowWin:   g.showWin,
+		Milestone: g.milestone,
+		NextSpawn: g.nextSpawn,
+		Record:    &g.record,
 	}
 
 	// 将对象序列化为JSON
@@ -659,7 +900,7 @@ func (g *Game) saveGame(showMessage bool) {
 	if err != nil {
 		log.Printf("保存游戏失败: %v", err)
 		if showMessage {
-			g.showMessage("保存游戏失败", 60)
+			g.showMessage(tr("msg.save_failed"), 60)
 		}
 		return
 	}
@@ -669,13 +910,13 @@ func (g *Game) saveGame(showMessage bool) {
 	if err != nil {
 		log.Printf("写入存档文件失败: %v", err)
 		if showMessage {
-			g.showMessage("保存游戏失败", 60)
+			g.showMessage(tr("msg.save_failed"), 60)
 		}
 		return
 	}
 
 	if showMessage {
-		g.showMessage("游戏已保存", 60)
+		g.showMessage(tr("msg.saved"), 60)
 	}
 }
 
@@ -683,7 +924,7 @@ func (g *Game) saveGame(showMessage bool) {
 func (g *Game) loadGame() bool {
 	// 检查文件是否存在
 	if _, err := os.Stat(saveFilePath); os.IsNotExist(err) {
-		g.showMessage("没有找到存档", 60)
+		g.showMessage(tr("msg.no_save"), 60)
 		return false
 	}
 
@@ -691,7 +932,7 @@ func (g *Game) loadGame() bool {
 	data, err := ioutil.ReadFile(saveFilePath)
 	if err != nil {
 		log.Printf("读取存档文件失败: %v", err)
-		g.showMessage("加载游戏失败", 60)
+		g.showMessage(tr("msg.load_failed"), 60)
 		return false
 	}
 
@@ -700,19 +941,48 @@ func (g *Game) loadGame() bool {
 	err = json.Unmarshal(data, &save)
 	if err != nil {
 		log.Printf("解析存档数据失败: %v", err)
-		g.showMessage("加载游戏失败", 60)
+		g.showMessage(tr("msg.load_failed"), 60)
+		return false
+	}
+
+	// 棋盘必须是受支持尺寸的正方形
+	if !validSquareBoard(save.Board) {
+		log.Printf("存档中的棋盘尺寸无效")
+		g.showMessage(tr("msg.load_failed"), 60)
 		return false
 	}
 
 	// 恢复游戏状态
+	setBoardSize(len(save.Board))
 	g.board = save.Board
+	g.previousBoard = newBoard(boardSize)
 	g.score = save.Score
 	g.bestScore = save.BestScore
 	g.gameOver = save.GameOver
 	g.win = save.Win
 	g.showWin = save.ShowWin
+	g.milestone = save.Milestone
+	g.nextSpawn = save.NextSpawn
+	g.undoStack = nil
+	g.moveQueue = nil
+	g.pendingMove = nil
+	g.finishAnimation()
+
+	// 旧存档没有对局记录，从当前局面开始记录
+	// 对局按记录中的规则继续，旧存档使用配置的规则
+	if save.Record != nil && save.Record.valid() {
+		g.record = *save.Record
+		currentRules = findRuleVariant(g.record.Rules)
+	} else {
+		currentRules = findRuleVariant(g.config.RuleVariant)
+		g.startRecord(false)
+	}
+	if g.win && g.milestone == 0 {
+		// 旧存档没有记录里程碑，按当前最大方块推算
+		g.milestone = currentRules.milestone(g.board.maxTile())
+	}
 
-	g.showMessage("游戏已加载", 60)
+	g.showMessage(tr("msg.loaded"), 60)
 	return true
 }
 
@@ -735,57 +1005,194 @@ func (g *Game) Update() error {
 	} else {
 		g.message = ""
 	}
+	g.updateToasts()
+
+	// 关闭窗口时先确认，确认对话框已经打开时直接退出
+	if ebiten.IsWindowBeingClosed() {
+		if _, ok := g.currentScene().(*confirmScene); ok {
+			g.quitRequested = true
+		} else {
+			g.requestQuit()
+		}
+	}
+
+	// 交给当前场景处理
+	g.updateScenes()
+	if g.quitRequested {
+		return ebiten.Termination
+	}
 
-	// 更新动画状态
+	return nil
+}
+
+// 更新游戏场景
+func (g *Game) updatePlaying() {
+	// 按实际经过的时间更新动画状态和游戏时间
+	dt := g.frameDelta()
+	if !g.gameOver {
+		g.record.PlayTime += dt
+	}
+	if g.advanceAnimation(dt) {
+		// 执行动画期间缓冲的移动
+		g.runQueuedMove()
+	}
+
+	// 动画结束后再显示结束或胜利界面
+	if !g.animating {
+		if g.gameOver {
+			g.openGameOver()
+			return
+		}
+		if g.win && g.showWin {
+			g.openWin()
+			return
+		}
+	}
+
+	// 处理鼠标和触摸输入
+	g.updatePointer()
+
+	// 处理手柄输入
+	for _, a := range g.padActions {
+		g.performAction(a)
+	}
+
+	// 处理按键输入，动画期间的移动会被缓冲
+	for a := Action(0); a < actionCount; a++ {
+		if g.keys.justPressed(a) {
+			g.performAction(a)
+			break
+		}
+	}
+}
+
+// 执行输入动作，按键、手柄和按钮共用
+func (g *Game) performAction(a Action) {
+	if dir, ok := a.direction(); ok {
+		g.requestMove(dir)
+		return
+	}
+
+	// 动画期间也可以暂停，动画在暂停时停止
+	if a == ActionPause {
+		g.openPause()
+		return
+	}
+
+	// 动画期间只缓冲移动，其他动作忽略
 	if g.animating {
-		g.animationProgress += 0.15  // 调快动画速度
-		if g.animationProgress >= 1.0 {
-			g.animating = false
-			g.animationProgress = 0
-			g.animations = []TileAnimation{}
+		return
+	}
+
+	switch a {
+	case ActionUndo:
+		// 撤销上一步
+		g.undo()
+	case ActionReset:
+		// 重置游戏，有进度时先确认
+		g.requestReset(nil)
+	case ActionSave:
+		// 手动保存游戏，显示提醒
+		g.saveGame(true)
+	case ActionLoad:
+		// 手动加载游戏，会覆盖当前进度时先确认
+		g.requestLoad()
+	case ActionEdit:
+		// 进入棋盘编辑模式
+		g.openEditor()
+	case ActionCopyPosition:
+		// 复制当前局面记谱
+		g.copyPosition()
+	case ActionControls:
+		// 打开按键设置界面
+		g.openControls()
+	case ActionCycleTheme:
+		// 切换主题
+		g.cycleTheme()
+	case ActionToggleMute:
+		// 静音开关
+		g.toggleMute()
+	case ActionVolumeUp:
+		g.changeVolume(volumeStep)
+	case ActionVolumeDown:
+		g.changeVolume(-volumeStep)
+	case ActionSettings:
+		// 打开设置界面
+		g.openSettings()
+	case ActionTraining:
+		// 训练提示开关
+		g.toggleTrainingOverlay()
+	case ActionAnalyze:
+		// 游戏结束后分析本局
+		if g.gameOver {
+			g.openAnalysis(g.resultRecord())
+		} else {
+			g.showMessage(tr("msg.analysis_unavailable"), 60)
 		}
 	}
+}
 
-	// 处理按键输入
-	if !g.animating {  // 只有在没有动画时才处理输入
-		if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
-			if g.move(DirectionUp) {
-				// 移动后自动保存游戏状态，但不显示提醒
-				g.saveGame(false)
-			}
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
-			if g.move(DirectionRight) {
-				g.saveGame(false)
-			}
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
-			if g.move(DirectionDown) {
-				g.saveGame(false)
-			}
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
-			if g.move(DirectionLeft) {
-				g.saveGame(false)
-			}
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-			// 重置游戏
-			g.resetGame()
-			g.showMessage("游戏已重置", 60)
-		} else if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-			// 如果已经赢了，继续游戏
-			if g.win && g.showWin {
-				g.showWin = false
-				g.showMessage("继续游戏", 60)
-				g.saveGame(false)
+// 请求移动，动画期间根据配置缓冲或立即结束当前动画
+func (g *Game) requestMove(direction int) {
+	// 没有缓冲的移动时，当前棋盘就是下一步的起点，不能移动的方向直接忽略
+	if !validDirection(direction) || len(g.moveQueue) == 0 && !g.legalMoves()[direction] {
+		return
+	}
+
+	if g.animating {
+		if !g.config.FinishAnimationOnInput {
+			if len(g.moveQueue) < g.config.InputBufferDepth {
+				g.moveQueue = append(g.moveQueue, direction)
 			}
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyS) {
-			// 手动保存游戏，显示提醒
-			g.saveGame(true)
-		} else if inpututil.IsKeyJustPressed(ebiten.KeyL) {
-			// 手动加载游戏
-			g.loadGame()
+			return
+		}
+		g.finishAnimation()
+
+		// 结束的动画达到了里程碑或结束了游戏，先显示对应的界面
+		if g.gameOver || g.win && g.showWin {
+			return
 		}
 	}
 
-	return nil
+	if g.move(direction) {
+		// 移动后自动保存游戏状态，但不显示提醒
+		g.saveGame(false)
+	}
+}
+
+// 按顺序执行缓冲的移动，跳过不会改变棋盘的移动
+func (g *Game) runQueuedMove() {
+	for len(g.moveQueue) > 0 {
+		direction := g.moveQueue[0]
+		g.moveQueue = g.moveQueue[1:]
+		if g.move(direction) {
+			g.saveGame(false)
+			return
+		}
+	}
+}
+
+// 立即结束当前动画
+func (g *Game) finishAnimation() {
+	g.animating = false
+	g.animationElapsed = 0
+	g.animations = []TileAnimation{}
+	g.settleMove()
+}
+
+// 动作的第一个按键名称，用于界面提示
+func (g *Game) keyHint(a Action) string {
+	if len(g.keys[a]) == 0 {
+		return "-"
+	}
+	return g.keys[a][0].String()
+}
+
+// 胜利后继续游戏
+func (g *Game) continueAfterWin() {
+	g.showWin = false
+	g.showMessage(tr("msg.continue"), 60)
+	g.saveGame(false)
 }
 
 // 显示消息
@@ -796,37 +1203,56 @@ func (g *Game) showMessage(msg string, time int) {
 
 // 绘制游戏界面
 func (g *Game) Draw(screen *ebiten.Image) {
+	// 绘制场景栈
+	g.drawScenes(screen)
+
+	// 如果有消息，显示消息
+	g.drawMessage(screen)
+	g.drawToast(screen)
+}
+
+// 绘制游戏场景
+func (g *Game) drawPlaying(screen *ebiten.Image) {
+	g.drawPlayfield(screen)
+
+	// 绘制操作按钮
+	drawButtons(screen)
+
+	// 绘制游戏说明
+	instructionText := tr("hint.main",
+		g.keyHint(ActionReset), g.keyHint(ActionUndo), g.keyHint(ActionSave), g.keyHint(ActionLoad), g.keyHint(ActionControls))
+	// 横屏时拆分成多行显示在侧边面板
+	for i, line := range layout.hintLines(instructionText) {
+		drawTextCentered(screen, line, scoreFont, layout.infoX, float64(layout.hintY+i*20), currentTheme.Text)
+	}
+
+	// 训练提示，动画期间不显示；关闭时只用方向标记提示哪些方向可以移动
+	// 胜利和结束界面由覆盖在上面的场景绘制
+	if g.config.ShowTraining && !g.animating && !g.gameOver {
+		g.drawTrainingOverlay(screen)
+	} else if !g.gameOver {
+		g.drawDirectionHints(screen)
+	}
+}
+
+// 绘制背景、标题、分数和棋盘，回放时也使用
+func (g *Game) drawPlayfield(screen *ebiten.Image) {
 	// 绘制背景
-	screen.Fill(backgroundColor)
+	screen.Fill(currentTheme.Background)
 
 	// 绘制游戏标题
-	text.Draw(screen, "2048", titleFont, screenWidth/2-50, 60, textColor)
+	drawTextCentered(screen, "2048", titleFont, layout.infoX, float64(layout.titleY), currentTheme.Text)
 
-	// 计算分数面板位置，使两侧边距相等
-	panelWidth := 100
-	leftPanelX := (screenWidth / 2 - panelWidth) / 2 - 45
-	rightPanelX := screenWidth / 2 + (screenWidth / 2 - panelWidth) / 2 + 45
-	
 	// 绘制分数
-	drawScorePanel(screen, "分数", g.score, leftPanelX, 90)
-	drawScorePanel(screen, "最高分", g.bestScore, rightPanelX, 90)
-
-	// 绘制游戏说明
-	instructionText := "R键重置 | S键保存 | L键加载"
-	// 计算文本宽度以居中显示
-	bounds, _ := font.BoundString(scoreFont, instructionText)
-	textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
-	
-	// 绘制半透明背景确保文字清晰可见
-	// ebitenutil.DrawRect(screen, float64(screenWidth/2-textWidth/2-10), 130, float64(textWidth+20), 30, color.RGBA{187, 173, 160, 200})
-	text.Draw(screen, instructionText, scoreFont, screenWidth/2-textWidth/2, 150, textColor)
+	drawScorePanel(screen, tr("score.current"), g.score, layout.panelLeftX, float64(layout.panelY))
+	drawScorePanel(screen, tr("score.best"), g.bestScore, layout.panelRightX, float64(layout.panelY))
 
 	// 绘制游戏棋盘(只绘制背景和空格)
 	drawBoard(screen, g.board)
 	
 	// 棋盘位置
-	boardX := (screenWidth - (tileSize*boardSize + tileMargin*(boardSize-1))) / 2
-	boardY := 180
+	boardX := layout.boardX
+	boardY := layout.boardY
 	
 	// 如果正在动画中，绘制动画方块
 	if g.animating {
@@ -846,331 +1272,95 @@ func (g *Game) Draw(screen *ebiten.Image) {
 				if !isTarget && g.board[i][j] > 0 {
 					x := boardX + j*(tileSize+tileMargin)
 					y := boardY + i*(tileSize+tileMargin)
-					
-					// 获取方块颜色
-					var tileColor color.RGBA
-					if val, ok := tileColors[g.board[i][j]]; ok {
-						tileColor = val
-					} else {
-						tileColor = tileColors[2048]
-					}
-					
-					// 绘制方块
-					ebitenutil.DrawRect(screen, float64(x), float64(y), float64(tileSize), float64(tileSize), tileColor)
-					
-					// 绘制数字
-					numStr := fmt.Sprintf("%d", g.board[i][j])
-					var tFace font.Face
-					
-					if g.board[i][j] < 100 {
-						tFace = boldFont
-					} else if g.board[i][j] < 1000 {
-						tFace = boldFont
-					} else {
-						tFace = boldFont
-					}
-					
-					// 计算文本位置
-					bounds, _ := font.BoundString(tFace, numStr)
-					textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
-					textHeight := (bounds.Max.Y - bounds.Min.Y).Ceil()
-					
-					textX := x + (tileSize-textWidth)/2
-					textY := y + (tileSize+textHeight)/2
-					
-					// 选择文本颜色
-					textCol := textColor
-					if g.board[i][j] > 4 {
-						textCol = textColorLight
-					}
-					
-					// 绘制数字
-					text.Draw(screen, numStr, tFace, textX, textY, textCol)
+					drawTile(screen, g.board[i][j], x, y)
 				}
 			}
 		}
 		
 		// 然后绘制动画中的方块
+		slideProgress, mergeProgress, spawnProgress := g.animationPhases()
 		for _, anim := range g.animations {
-			progress := easeOutQuad(g.animationProgress)
-			
-			// 计算动画位置
-			var currentX, currentY float64
-			
-			// 移动动画
-			currentX = float64(boardX) + float64(anim.fromX)*(tileSize+tileMargin) + 
-					   float64(anim.toX-anim.fromX)*(tileSize+tileMargin)*progress
-			currentY = float64(boardY) + float64(anim.fromY)*(tileSize+tileMargin) + 
-					   float64(anim.toY-anim.fromY)*(tileSize+tileMargin)*progress
-			
-			// 获取方块颜色
-			var tileColor color.RGBA
-			if val, ok := tileColors[anim.value]; ok {
-				tileColor = val
-			} else {
-				tileColor = tileColors[2048]
-			}
-			
-			// 如果是合并动画，添加缩放和透明度效果
-			var scale, alpha float64
-			if anim.animType == AnimationMerge && progress > 0.5 {
-				// 在移动完成后半段添加合并效果
-				// 先稍微放大
-				p := (progress - 0.5) * 2 // 将0.5-1.0映射到0-1.0
-				scale = 1.0 + 0.3*sinWave(p) // 使用正弦波实现缩放效果
-				
-				// 计算透明度变化
-				alpha = sinWave(p)
-			} else {
-				scale = 1.0
-				alpha = 1.0
-			}
-			
-			// 计算缩放后的尺寸和位置
-			scaledSize := float64(tileSize) * scale
-			offsetX := (scaledSize - float64(tileSize)) / 2
-			offsetY := (scaledSize - float64(tileSize)) / 2
-			
-			// 调整颜色透明度
-			if anim.animType == AnimationMerge && progress > 0.5 {
-				tileColor.A = uint8(255 * alpha)
-			}
-			
-			// 绘制方块
-			ebitenutil.DrawRect(screen, currentX-offsetX, currentY-offsetY, scaledSize, scaledSize, tileColor)
-			
-			// 如果是合并动画且在后半段，不绘制数字（会在目标格子绘制）
-			if !(anim.animType == AnimationMerge && progress > 0.85) {
-				// 绘制数字
-				numStr := fmt.Sprintf("%d", anim.value)
-				var tFace font.Face
-				
-				if anim.value < 100 {
-					tFace = boldFont
-				} else if anim.value < 1000 {
-					tFace = boldFont
-				} else {
-					tFace = boldFont
+			fromX := float64(boardX + anim.fromX*(tileSize+tileMargin))
+			fromY := float64(boardY + anim.fromY*(tileSize+tileMargin))
+			toX := float64(boardX + anim.toX*(tileSize+tileMargin))
+			toY := float64(boardY + anim.toY*(tileSize+tileMargin))
+
+			// 新方块在滑动结束后从中心放大出现
+			if anim.animType == AnimationSpawn {
+				if spawnProgress > 0 {
+					drawTileScaled(screen, anim.value, toX, toY, easeOutQuad(spawnProgress), currentTheme.tileColor(anim.value))
 				}
-				
-				// 计算文本位置
-				bounds, _ := font.BoundString(tFace, numStr)
-				textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
-				textHeight := (bounds.Max.Y - bounds.Min.Y).Ceil()
-				
-				textX := int(currentX) + int(scaledSize-float64(textWidth))/2
-				textY := int(currentY) + int(scaledSize+float64(textHeight))/2
-				
-				// 选择文本颜色
-				textCol := textColor
-				if anim.value > 4 {
-					textCol = textColorLight
-				}
-				
-				// 调整文本透明度
-				if anim.animType == AnimationMerge && progress > 0.5 {
-					textCol = color.RGBA{textCol.R, textCol.G, textCol.B, uint8(255 * alpha)}
-				}
-				
-				// 绘制数字
-				text.Draw(screen, numStr, tFace, textX, textY, textCol)
+				continue
 			}
-			
-			// 如果是合并动画且在靠近结束阶段，绘制目标值的数字
-			if anim.animType == AnimationMerge && progress > 0.85 {
-				targetX := boardX + anim.toX*(tileSize+tileMargin)
-				targetY := boardY + anim.toY*(tileSize+tileMargin)
+
+			// 合并弹出阶段：在目标位置绘制合并后的方块
+			if anim.animType == AnimationMerge && mergeProgress > 0 {
 				targetValue := anim.value * 2
-				
-				// 获取目标方块颜色和源方块颜色
-				var targetColor, sourceColor color.RGBA
-				if val, ok := tileColors[targetValue]; ok {
-					targetColor = val
-				} else {
-					targetColor = tileColors[2048]
-				}
-				
-				if val, ok := tileColors[anim.value]; ok {
-					sourceColor = val
-				} else {
-					sourceColor = tileColors[2048]
-				}
-				
-				// 计算从0到1的渐变进度
-				fadeInProgress := (progress - 0.85) / 0.15
-				
-				// 颜色过渡效果
-				currentColor := lerpColor(sourceColor, targetColor, fadeInProgress)
-				
-				// 计算缩放效果 - 使用弹性函数
-				targetScale := 1.0 + 0.3*elasticOut(fadeInProgress)
-				targetSize := float64(tileSize) * targetScale
-				targetOffsetX := (targetSize - float64(tileSize)) / 2
-				targetOffsetY := (targetSize - float64(tileSize)) / 2
-				
-				// 绘制目标方块
-				ebitenutil.DrawRect(screen, float64(targetX)-targetOffsetX, float64(targetY)-targetOffsetY, 
-									targetSize, targetSize, currentColor)
-				
+
+				// 颜色从源方块过渡到目标方块，同时放大后回弹
+				currentColor := lerpColor(currentTheme.tileColor(anim.value), currentTheme.tileColor(targetValue), sinWave(mergeProgress))
+				scale := 1.0 + 0.2*math.Sin(mergeProgress*math.Pi)
+				drawTileScaled(screen, targetValue, toX, toY, scale, currentColor)
+
 				// 绘制闪光效果
-				if fadeInProgress > 0.3 && fadeInProgress < 0.7 {
-					glowIntensity := 1.0 - math.Abs(fadeInProgress - 0.5) * 5.0 // 0.5时最强
+				if mergeProgress > 0.3 && mergeProgress < 0.7 {
+					glowIntensity := 1.0 - math.Abs(mergeProgress-0.5)*5.0 // 0.5时最强
 					glowColor := color.RGBA{255, 255, 255, uint8(100 * glowIntensity)}
-					glowSize := targetSize + 10*glowIntensity
-					ebitenutil.DrawRect(screen, float64(targetX)-(glowSize-float64(tileSize))/2, 
-										float64(targetY)-(glowSize-float64(tileSize))/2, 
-										glowSize, glowSize, glowColor)
-				}
-				
-				// 绘制目标数字
-				numStr := fmt.Sprintf("%d", targetValue)
-				var tFace font.Face
-				
-				if targetValue < 100 {
-					tFace = boldFont
-				} else if targetValue < 1000 {
-					tFace = boldFont
-				} else {
-					tFace = boldFont
-				}
-				
-				// 计算文本位置
-				bounds, _ := font.BoundString(tFace, numStr)
-				textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
-				textHeight := (bounds.Max.Y - bounds.Min.Y).Ceil()
-				
-				textX := targetX + int(targetSize-float64(textWidth))/2
-				textY := targetY + int(targetSize+float64(textHeight))/2
-				
-				// 选择文本颜色
-				textCol := textColor
-				if targetValue > 4 {
-					textCol = textColorLight
+					glowSize := float64(tileSize)*scale + 10*glowIntensity
+					drawRect(screen, toX-(glowSize-float64(tileSize))/2,
+						toY-(glowSize-float64(tileSize))/2,
+						glowSize, glowSize, glowColor)
 				}
-				
-				// 数字动画效果
-				textScale := 1.0 + 0.2*(1.0-fadeInProgress)
-				
-				// 绘制数字
-				op := &ebiten.DrawImageOptions{}
-				textImg := ebiten.NewImage(textWidth+10, textHeight+10)
-				text.Draw(textImg, numStr, tFace, 5, textHeight, textCol)
-				
-				op.GeoM.Translate(-float64(textWidth+10)/2, -float64(textHeight+10)/2)
-				op.GeoM.Scale(textScale, textScale)
-				op.GeoM.Translate(float64(textX+textWidth/2), float64(textY-textHeight/2))
-				
-				screen.DrawImage(textImg, op)
+				continue
 			}
+
+			// 滑动阶段：按缓动曲线插值位置
+			progress := easeOutQuad(slideProgress)
+			currentX := fromX + (toX-fromX)*progress
+			currentY := fromY + (toY-fromY)*progress
+			drawTileScaled(screen, anim.value, currentX, currentY, 1.0, currentTheme.tileColor(anim.value))
 		}
 	} else {
 		// 正常绘制所有方块(非动画状态)
-		for i := 0; i < boardSize; i++ {
-			for j := 0; j < boardSize; j++ {
-				if g.board[i][j] > 0 {
-					// 计算方块位置
-					x := boardX + j*(tileSize+tileMargin)
-					y := boardY + i*(tileSize+tileMargin)
-					
-					// 获取方块颜色
-					var tileColor color.RGBA
-					if val, ok := tileColors[g.board[i][j]]; ok {
-						tileColor = val
-					} else {
-						tileColor = tileColors[2048]
-					}
-					
-					// 绘制方块
-					ebitenutil.DrawRect(screen, float64(x), float64(y), float64(tileSize), float64(tileSize), tileColor)
-					
-					// 绘制数字
-					numStr := fmt.Sprintf("%d", g.board[i][j])
-					var tFace font.Face
-					
-					if g.board[i][j] < 100 {
-						tFace = boldFont
-					} else if g.board[i][j] < 1000 {
-						tFace = boldFont
-					} else {
-						tFace = boldFont
-					}
-					
-					// 计算文本位置
-					bounds, _ := font.BoundString(tFace, numStr)
-					textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
-					textHeight := (bounds.Max.Y - bounds.Min.Y).Ceil()
-					
-					textX := x + (tileSize-textWidth)/2
-					textY := y + (tileSize+textHeight)/2
-					
-					// 选择文本颜色
-					textCol := textColor
-					if g.board[i][j] > 4 {
-						textCol = textColorLight
-					}
-					
-					// 绘制数字
-					text.Draw(screen, numStr, tFace, textX, textY, textCol)
-				}
-			}
-		}
-	}
-
-	// 如果游戏胜利，显示胜利信息
-	if g.win && g.showWin {
-		drawOverlay(screen, "恭喜你赢了!", "按空格键继续游戏")
-	}
-
-	// 如果游戏结束，显示结束信息
-	if g.gameOver {
-		drawOverlay(screen, "游戏结束!", "按R键重新开始")
+		drawTiles(screen, g.board)
 	}
+}
 
-	// 如果有消息，显示消息
+// 绘制消息提示
+func (g *Game) drawMessage(screen *ebiten.Image) {
 	if g.message != "" {
-		messageWidth := len(g.message) * 20
-		ebitenutil.DrawRect(screen, float64(screenWidth/2-messageWidth/2-10), 180, float64(messageWidth+20), 40, color.RGBA{0, 0, 0, 180})
-		text.Draw(screen, g.message, boldFont, screenWidth/2-messageWidth/2, 205, color.White)
+		// 消息显示在棋盘顶部居中
+		centerX := float64(layout.boardX) + boardPixels/2
+		top := float64(layout.boardY)
+		messageWidth, _ := textSize(boldFont, g.message)
+		drawRect(screen, centerX-messageWidth/2-10, top, messageWidth+20, 40, color.RGBA{0, 0, 0, 180})
+		drawText(screen, g.message, boldFont, centerX-messageWidth/2, top+25, color.White)
 	}
 }
 
 // 绘制分数面板
-func drawScorePanel(screen *ebiten.Image, title string, score int, x, y int) {
-	panelWidth := 100  // 调整面板宽度，使左右间距一致
-	panelHeight := 60
-	
+func drawScorePanel(screen *ebiten.Image, title string, score int, x, y float64) {
 	// 绘制背景
-	ebitenutil.DrawRect(screen, float64(x), float64(y), float64(panelWidth), float64(panelHeight), boardColor)
-	
-	// 计算标题文本宽度居中显示
-	titleBounds, _ := font.BoundString(scoreFont, title)
-	titleWidth := (titleBounds.Max.X - titleBounds.Min.X).Ceil()
-	titleX := x + (panelWidth - titleWidth) / 2
+	drawRect(screen, x, y, scorePanelWidth, scorePanelHeight, currentTheme.Board)
 	
 	// 绘制标题
-	text.Draw(screen, title, scoreFont, titleX, y+20, textColorLight)
-	
-	// 计算分数文本宽度居中显示
-	scoreText := fmt.Sprintf("%d", score)
-	scoreBounds, _ := font.BoundString(boldFont, scoreText)
-	scoreWidth := (scoreBounds.Max.X - scoreBounds.Min.X).Ceil()
-	scoreX := x + (panelWidth - scoreWidth) / 2
+	drawTextCentered(screen, title, scoreFont, x+scorePanelWidth/2, y+20, currentTheme.TextLight)
 	
 	// 绘制分数
-	text.Draw(screen, scoreText, boldFont, scoreX, y+45, textColorLight)
+	drawTextCentered(screen, fmt.Sprintf("%d", score), boldFont, x+scorePanelWidth/2, y+45, currentTheme.TextLight)
 }
 
 // 绘制棋盘
-func drawBoard(screen *ebiten.Image, board [boardSize][boardSize]int) {
+func drawBoard(screen *ebiten.Image, board Board) {
 	// 棋盘位置
-	boardX := (screenWidth - (tileSize*boardSize + tileMargin*(boardSize-1))) / 2
-	boardY := 180
+	boardX := layout.boardX
+	boardY := layout.boardY
 
 	// 绘制棋盘背景
-	ebitenutil.DrawRect(screen, float64(boardX-boardMargin), float64(boardY-boardMargin), 
-		float64((tileSize+tileMargin)*boardSize+boardMargin-tileMargin+boardMargin), 
-		float64((tileSize+tileMargin)*boardSize+boardMargin-tileMargin+boardMargin), 
-		boardColor)
+	drawRect(screen, float64(boardX-boardMargin), float64(boardY-boardMargin), 
+		float64(gridPixels()+2*boardMargin), 
+		float64(gridPixels()+2*boardMargin), 
+		currentTheme.Board)
 
 	// 绘制每个格子
 	for i := 0; i < boardSize; i++ {
@@ -1179,142 +1369,134 @@ func drawBoard(screen *ebiten.Image, board [boardSize][boardSize]int) {
 			y := boardY + i*(tileSize+tileMargin)
 			
 			// 绘制空白格背景
-			ebitenutil.DrawRect(screen, float64(x), float64(y), float64(tileSize), float64(tileSize), emptyTileColor)
+			drawRect(screen, float64(x), float64(y), float64(tileSize), float64(tileSize), currentTheme.EmptyTile)
+		}
+	}
+}
+
+// 绘制棋盘上的所有方块
+func drawTiles(screen *ebiten.Image, board Board) {
+	boardX := layout.boardX
+	boardY := layout.boardY
+
+	for i := 0; i < boardSize; i++ {
+		for j := 0; j < boardSize; j++ {
+			if board[i][j] > 0 {
+				x := boardX + j*(tileSize+tileMargin)
+				y := boardY + i*(tileSize+tileMargin)
+				drawTile(screen, board[i][j], x, y)
+			}
 		}
 	}
 }
 
+// 绘制单个方块
+func drawTile(screen *ebiten.Image, value int, x, y int) {
+	drawTileScaled(screen, value, float64(x), float64(y), 1.0, currentTheme.tileColor(value))
+}
+
+// 以格子左上角为基准绘制缩放后的方块，缩放围绕格子中心
+func drawTileScaled(screen *ebiten.Image, value int, x, y, scale float64, tileColor color.RGBA) {
+	// 计算缩放后的尺寸和位置
+	size := float64(tileSize) * scale
+	offset := (size - float64(tileSize)) / 2
+
+	// 绘制方块
+	drawRect(screen, x-offset, y-offset, size, size, tileColor)
+
+	// 方块过小时不绘制数字
+	if scale < 0.6 {
+		return
+	}
+
+	// 按位数选择字号，计算文本位置
+	numStr := tileLabel(value)
+	face := tileFace(numStr)
+	textWidth, textHeight := textSize(face, numStr)
+
+	textX := x + (float64(tileSize)-textWidth)/2
+	textY := y + (float64(tileSize)+textHeight)/2
+
+	// 选择文本颜色
+	textCol := currentTheme.tileTextColor(value)
+
+	// 绘制数字
+	drawText(screen, numStr, face, textX, textY, textCol)
+}
+
 // 绘制覆盖层
 func drawOverlay(screen *ebiten.Image, title, subtitle string) {
 	// 绘制半透明背景
-	ebitenutil.DrawRect(screen, 0, 0, float64(screenWidth), float64(screenHeight), color.RGBA{0, 0, 0, 180})
+	drawScreenShade(screen, color.RGBA{0, 0, 0, 180})
 	
 	// 绘制标题
-	titleWidth := len(title) * 15
-	text.Draw(screen, title, titleFont, screenWidth/2-titleWidth/2, screenHeight/2-40, color.White)
+	centerX, centerY := layout.canvasWidth/2, layout.canvasHeight/2
+	drawTextCentered(screen, title, titleFont, centerX, centerY-40, color.White)
 	
 	// 绘制副标题
-	subtitleWidth := len(subtitle) * 10
-	text.Draw(screen, subtitle, boldFont, screenWidth/2-subtitleWidth/2, screenHeight/2+10, color.White)
+	drawTextCentered(screen, subtitle, boldFont, centerX, centerY+10, color.White)
 }
 
+// 按窗口的实际像素尺寸布局，高分屏下乘以设备缩放比例
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+	s := ebiten.DeviceScaleFactor()
+	width := int(math.Ceil(float64(outsideWidth) * s))
+	height := int(math.Ceil(float64(outsideHeight) * s))
+
+	if width != layout.width || height != layout.height {
+		layout = newScreenLayout(width, height)
+		setFontScale(layout.scale)
+	}
+	return width, height
 }
 
+// 启动时指定的局面记谱
+var positionFlag = flag.String("position", "", "从指定局面开始游戏，例如 \"1200/0000/0013/a000 p 0\"")
+
 func main() {
+	flag.Parse()
+
 	// 设置随机种子
 	rand.Seed(time.Now().UnixNano())
 
-	// 加载字体
+	// 加载语言包和字体
+	loadCatalogs()
 	loadFonts()
 
 	// 创建游戏
 	game := NewGame()
 
+	// 从命令行指定的局面开始
+	if *positionFlag != "" {
+		pos, err := parsePosition(*positionFlag)
+		if err != nil {
+			log.Fatalf("无效的局面记谱: %v", err)
+		}
+		if err := validatePosition(pos); err != nil {
+			log.Fatalf("无效的局面: %v", err)
+		}
+		game.setPosition(pos)
+	} else {
+		// 从标题菜单开始
+		game.replaceScenes(newTitleScene(game))
+	}
+
 	// 设置窗口标题
-	ebiten.SetWindowTitle("2048游戏")
+	ebiten.SetWindowTitle(tr("window.title"))
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowResizable(true)
+	ebiten.SetWindowClosingHandled(true)
 
 	// 运行游戏
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
 	
-	// 程序正常退出时保存游戏并显示提醒
+	// 程序正常退出时先结算播放中的最后一步，再保存游戏并显示提醒
+	game.finishAnimation()
 	game.saveGame(true)
 }
 
-// 加载字体
-func loadFonts() {
-	// 加载默认字体
-	tt, err := opentype.Parse(fonts.MPlus1pRegular_ttf)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 普通字体
-	normalFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    12,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 粗体字体
-	boldFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    16,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 标题字体
-	titleFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    32,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 分数字体
-	scoreFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    14,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 加载中文字体
-	fontData, err := os.ReadFile("asset/zzgf_dianhei.otf")
-	if err != nil {
-		log.Printf("无法加载中文字体: %v", err)
-		return
-	}
-
-	tt, err = opentype.Parse(fontData)
-	if err != nil {
-		log.Printf("无法解析中文字体: %v", err)
-		return
-	}
-
-	chineseFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    16,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Printf("无法创建中文字体: %v", err)
-		return
-	}
-
-	// 替换字体
-	boldFont = chineseFont
-	normalFont = chineseFont
-	
-	// 重新创建标题字体和分数字体
-	titleFont, _ = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    32,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	
-	scoreFont, _ = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    14,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-}
-
 // 缓动函数：缓出二次方
 func easeOutQuad(t float64) float64 {
 	return t * (2 - t)
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 菜单布局
+const (
+	menuItemWidth  = 260
+	menuItemHeight = 30
+	menuItemGap    = 6
+)
+
+// menuItem 菜单中的一项
+type menuItem struct {
+	key string        // 文字的消息键
+	run func(g *Game) // 选中后执行
+}
+
+// menu 纵向排列的菜单，支持方向键、回车、鼠标、触摸和手柄
+type menu struct {
+	items    []menuItem
+	selected int
+	cancel   func(g *Game) // 手柄B键执行，为 nil 时忽略
+}
+
+// 第 i 项的位置，菜单在画布上水平居中，从 top 开始排列
+func (m *menu) itemRect(i int, top float64) (x, y float64) {
+	x = layout.canvasWidth/2 - menuItemWidth/2
+	y = top + float64(i*(menuItemHeight+menuItemGap))
+	return x, y
+}
+
+// 坐标处的菜单项，没有时返回 -1
+func (m *menu) itemAt(px, py int, top float64) int {
+	for i := range m.items {
+		x, y := m.itemRect(i, top)
+		if float64(px) >= x && float64(px) < x+menuItemWidth && float64(py) >= y && float64(py) < y+menuItemHeight {
+			return i
+		}
+	}
+	return -1
+}
+
+// 处理菜单输入
+func (m *menu) update(g *Game, top float64) {
+	n := len(m.items)
+	if n == 0 {
+		return
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp), g.keys.justPressed(ActionMoveUp), g.padPressed(ActionMoveUp):
+		m.selected = stepIndex(m.selected, -1, n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown), g.keys.justPressed(ActionMoveDown), g.padPressed(ActionMoveDown):
+		m.selected = stepIndex(m.selected, 1, n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter),
+		inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter),
+		inpututil.IsKeyJustPressed(ebiten.KeySpace),
+		g.padPressed(ActionContinue): // 手柄A键
+		m.items[m.selected].run(g)
+		return
+	case g.padPressed(ActionUndo) && m.cancel != nil: // 手柄B键
+		m.cancel(g)
+		return
+	}
+
+	// 鼠标悬停选中，点击或触摸执行
+	x, y := cursorPosition()
+	if i := m.itemAt(x, y, top); i >= 0 {
+		m.selected = i
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			m.items[i].run(g)
+			return
+		}
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := touchPosition(id)
+		if i := m.itemAt(x, y, top); i >= 0 {
+			m.selected = i
+			m.items[i].run(g)
+			return
+		}
+	}
+}
+
+// 绘制菜单，选中项高亮
+func (m *menu) draw(screen *ebiten.Image, top float64) {
+	for i, item := range m.items {
+		x, y := m.itemRect(i, top)
+		drawRect(screen, x, y, menuItemWidth, menuItemHeight, currentTheme.Board)
+		if i == m.selected {
+			drawRect(screen, x, y, menuItemWidth, menuItemHeight, buttonHoverColor)
+			drawRect(screen, x, y+menuItemHeight-3, menuItemWidth, 3, currentTheme.TextLight)
+		}
+		drawTextCentered(screen, tr(item.key), boldFont, x+menuItemWidth/2, y+menuItemHeight/2+6, currentTheme.TextLight)
+	}
+}
+
+// 菜单的总高度
+func (m *menu) height() float64 {
+	return float64(len(m.items)*(menuItemHeight+menuItemGap) - menuItemGap)
+}
+
+// 标题菜单场景，启动游戏时显示
+type titleScene struct {
+	menu menu
+}
+
+// 创建标题菜单，有未完成的游戏时提供继续游戏
+func newTitleScene(g *Game) *titleScene {
+	s := &titleScene{}
+	if g.hasProgress() {
+		s.menu.items = append(s.menu.items, menuItem{"menu.continue", func(g *Game) {
+			g.replaceScenes(playScene{})
+		}})
+	}
+	s.menu.items = append(s.menu.items,
+		menuItem{"menu.new_game", func(g *Game) {
+			g.requestReset(func(g *Game) {
+				g.replaceScenes(playScene{})
+			})
+		}},
+		menuItem{"menu.settings", (*Game).openSettings},
+		menuItem{"menu.leaderboard", (*Game).openLeaderboard},
+		menuItem{"menu.achievements", (*Game).openAchievements},
+		menuItem{"menu.stats", (*Game).openStats},
+		menuItem{"menu.quit", func(g *Game) {
+			g.quitRequested = true
+		}},
+	)
+	return s
+}
+
+// 菜单的起始纵坐标，位于标题下方并在画布中居中
+func (s *titleScene) menuTop() float64 {
+	return (layout.canvasHeight-s.menu.height())/2 + 40
+}
+
+func (s *titleScene) update(g *Game) {
+	s.menu.update(g, s.menuTop())
+}
+
+func (s *titleScene) draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	top := s.menuTop()
+	drawTextCentered(screen, "2048", titleFont, centerX, top-70, currentTheme.Text)
+	drawTextCentered(screen, tr("menu.best", g.bestScore), boldFont, centerX, top-30, currentTheme.Text)
+	s.menu.draw(screen, top)
+}
+
+func (s *titleScene) overlay() bool { return false }
+
+// 暂停菜单场景，覆盖在游戏界面上
+//
+// 暂停期间游戏场景不再更新，动画、游戏时间和缓冲的输入都停止。
+type pauseScene struct {
+	menu menu
+}
+
+// 打开暂停菜单
+func (g *Game) openPause() {
+	// 丢弃暂停前缓冲的移动和未完成的手势
+	g.moveQueue = nil
+	g.pointer = pointerTracker{}
+
+	s := &pauseScene{}
+	s.menu.items = []menuItem{
+		{"pause.resume", (*Game).popScene},
+		{"pause.restart", func(g *Game) {
+			g.popScene()
+			g.requestReset(nil)
+		}},
+		{"menu.settings", (*Game).openSettings},
+		{"pause.main_menu", func(g *Game) {
+			g.saveGame(false)
+			g.replaceScenes(newTitleScene(g))
+		}},
+		{"menu.quit", (*Game).requestQuit},
+	}
+	s.menu.cancel = (*Game).popScene
+	g.pushScene(s)
+}
+
+// 菜单位于覆盖层标题和提示的下方
+func (s *pauseScene) menuTop() float64 {
+	return layout.canvasHeight/2 + 40
+}
+
+func (s *pauseScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || g.keys.justPressed(ActionPause) {
+		g.popScene()
+		return
+	}
+	s.menu.update(g, s.menuTop())
+}
+
+func (s *pauseScene) draw(g *Game, screen *ebiten.Image) {
+	drawOverlay(screen, tr("pause.title"), tr("pause.hint", g.keyHint(ActionPause)))
+	s.menu.draw(screen, s.menuTop())
+}
+
+func (s *pauseScene) overlay() bool { return true }
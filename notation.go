@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 记谱法中单个格子可表示的最大指数（'z' = 2^35）
+const maxTileExponent = 35
+
+// 指数对应的记谱字符：0-9 之后依次使用 a-z
+const exponentDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// 将方块数值转换为指数（空格为 0），非 2 的幂返回 -1
+func tileExponent(value int) int {
+	if value == 0 {
+		return 0
+	}
+	if value < 2 || value&(value-1) != 0 {
+		return -1
+	}
+	exp := 0
+	for value > 1 {
+		value >>= 1
+		exp++
+	}
+	return exp
+}
+
+// 将棋盘格式化为紧凑记谱，例如 "1200/0000/0013/a000"
+// 每行一段，用 / 分隔，每个字符表示该格方块的指数
+func formatBoard(board Board) string {
+	var sb strings.Builder
+	for i := range board {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		for j := range board[i] {
+			exp := tileExponent(board[i][j])
+			if exp < 0 || exp > maxTileExponent {
+				exp = 0
+			}
+			sb.WriteByte(exponentDigits[exp])
+		}
+	}
+	return sb.String()
+}
+
+// 解析紧凑记谱为棋盘，行数决定棋盘尺寸
+func parseBoard(s string) (Board, error) {
+	rows := strings.Split(strings.TrimSpace(s), "/")
+	size := len(rows)
+	if !validBoardSize(size) {
+		return nil, fmt.Errorf("记谱应有%d到%d行，实际为%d行", minBoardSize, maxBoardSize, size)
+	}
+
+	board := newBoard(size)
+	for i, row := range rows {
+		if len(row) != size {
+			return nil, fmt.Errorf("第%d行应有%d格，实际为%d格", i+1, size, len(row))
+		}
+		for j := 0; j < size; j++ {
+			exp := strings.IndexByte(exponentDigits, lowerASCII(row[j]))
+			if exp < 0 {
+				return nil, fmt.Errorf("第%d行第%d格含有无效字符 %q", i+1, j+1, row[j])
+			}
+			if exp > 0 {
+				board[i][j] = 1 << exp
+			}
+		}
+	}
+
+	return board, nil
+}
+
+// 将 ASCII 大写字母转换为小写
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// 局面中轮到行动的一方
+const (
+	SidePlayer = 'p' // 轮到玩家滑动
+	SideSpawn  = 's' // 等待生成新方块
+)
+
+// Position 描述一个完整局面，可与紧凑记谱互相转换
+type Position struct {
+	Board     Board
+	Side      byte
+	Score     int
+	NextSpawn *TileSpawn
+}
+
+// 将局面格式化为规范记谱：<棋盘> <行动方> <分数> [<下一个生成>]
+// 例如 "1200/0000/0013/a000 p 1024 c2=4"
+func formatPosition(pos Position) string {
+	side := pos.Side
+	if side != SideSpawn {
+		side = SidePlayer
+	}
+
+	s := fmt.Sprintf("%s %c %d", formatBoard(pos.Board), side, pos.Score)
+	if pos.NextSpawn != nil {
+		s += " " + formatSpawn(*pos.NextSpawn)
+	}
+	return s
+}
+
+// 解析规范记谱，行动方、分数和下一个生成均可省略
+func parsePosition(s string) (Position, error) {
+	pos := Position{Side: SidePlayer}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return pos, errors.New("记谱为空")
+	}
+	if len(fields) > 4 {
+		return pos, errors.New("记谱字段过多")
+	}
+
+	board, err := parseBoard(fields[0])
+	if err != nil {
+		return pos, err
+	}
+	pos.Board = board
+
+	if len(fields) > 1 {
+		switch fields[1] {
+		case string(SidePlayer), string(SideSpawn):
+			pos.Side = fields[1][0]
+		default:
+			return pos, fmt.Errorf("无效的行动方 %q", fields[1])
+		}
+	}
+
+	if len(fields) > 2 {
+		score, err := strconv.Atoi(fields[2])
+		if err != nil || score < 0 {
+			return pos, fmt.Errorf("无效的分数 %q", fields[2])
+		}
+		pos.Score = score
+	}
+
+	if len(fields) > 3 && fields[3] != "-" {
+		spawn, err := parseSpawn(fields[3], len(board))
+		if err != nil {
+			return pos, err
+		}
+		pos.NextSpawn = &spawn
+	}
+
+	return pos, nil
+}
+
+// 将生成方块格式化为 "<列字母><行号>=<数值>"，例如 "c2=4"
+func formatSpawn(spawn TileSpawn) string {
+	return fmt.Sprintf("%c%d=%d", 'a'+spawn.Col, spawn.Row+1, spawn.Value)
+}
+
+// 解析生成方块记谱，size 为棋盘尺寸
+func parseSpawn(s string, size int) (TileSpawn, error) {
+	var spawn TileSpawn
+	if len(s) < 4 || s[2] != '=' {
+		return spawn, fmt.Errorf("无效的生成方块 %q", s)
+	}
+
+	col := int(lowerASCII(s[0]) - 'a')
+	row := int(s[1] - '1')
+	if col < 0 || col >= size || row < 0 || row >= size {
+		return spawn, fmt.Errorf("生成位置超出棋盘 %q", s)
+	}
+
+	value, err := strconv.Atoi(s[3:])
+	if err != nil || (value != 2 && value != 4) {
+		return spawn, fmt.Errorf("无效的生成数值 %q", s)
+	}
+
+	spawn.Row, spawn.Col, spawn.Value = row, col, value
+	return spawn, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPositionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  Position
+		want string
+	}{
+		{
+			name: "玩家行动",
+			pos: Position{
+				Board: Board{{2, 4, 0, 0}, {0, 0, 0, 0}, {0, 0, 2, 8}, {1024, 0, 0, 0}},
+				Side:  SidePlayer,
+				Score: 1024,
+			},
+			want: "1200/0000/0013/a000 p 1024",
+		},
+		{
+			name: "等待生成并指定下一个方块",
+			pos: Position{
+				Board:     Board{{0, 0, 0}, {0, 2, 0}, {0, 0, 4}},
+				Side:      SideSpawn,
+				Score:     4,
+				NextSpawn: &TileSpawn{Row: 1, Col: 2, Value: 4},
+			},
+			want: "000/010/002 s 4 c2=4",
+		},
+		{
+			name: "大方块",
+			pos: Position{
+				Board: Board{{1 << 35, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 65536}},
+				Side:  SidePlayer,
+			},
+			want: "z000/0000/0000/000g p 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatPosition(tt.pos)
+			if got != tt.want {
+				t.Fatalf("formatPosition() = %q, want %q", got, tt.want)
+			}
+			parsed, err := parsePosition(got)
+			if err != nil {
+				t.Fatalf("parsePosition(%q) error: %v", got, err)
+			}
+			if !reflect.DeepEqual(parsed, tt.pos) {
+				t.Fatalf("parsePosition(%q) = %+v, want %+v", got, parsed, tt.pos)
+			}
+		})
+	}
+}
+
+func TestParsePositionRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"空记谱", ""},
+		{"字段过多", "0000/0000/0000/0000 p 0 a1=2 x"},
+		{"未知行动方", "0000/0000/0000/0000 x 0"},
+		{"负分数", "0000/0000/0000/0000 p -4"},
+		{"非数字分数", "0000/0000/0000/0000 p abc"},
+		{"生成缺少等号", "0000/0000/0000/0000 p 0 a1-2"},
+		{"生成位置超出棋盘", "0000/0000/0000/0000 p 0 e1=2"},
+		{"生成数值不是2或4", "0000/0000/0000/0000 p 0 a1=8"},
+		{"生成数值不是2的幂", "0000/0000/0000/0000 p 0 a1=3"},
+		{"行长度不一致", "0000/000/0000/0000 p 0"},
+		{"方块字符无效", "0000/00#0/0000/0000 p 0"},
+		{"行数过少", "00/00 p 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if pos, err := parsePosition(tt.input); err == nil {
+				t.Fatalf("parsePosition(%q) = %+v, want error", tt.input, pos)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 结束和胜利界面的出现时机
+const (
+	outcomeDelay    = 500 * time.Millisecond // 动画结束后先停留，让玩家看清最后的棋盘
+	outcomeFadeTime = 330 * time.Millisecond // 淡入的时长
+)
+
+// 结束和胜利界面遮罩的最大透明度，与 drawOverlay 一致
+const outcomeShadeAlpha = 180
+
+// 里程碑庆祝动画
+const (
+	celebrationTileSize   = 72                      // 中央放大显示的方块边长
+	celebrationCycle      = 1000 * time.Millisecond // 一轮火花从方块向外飞散的时长
+	celebrationSpin       = 0.6                     // 火花整体旋转的速度（弧度/秒）
+	celebrationPulse      = 6.0                     // 方块跳动的角频率（弧度/秒）
+	celebrationSparkBase  = 8                       // 第一个里程碑的火花数，之后每翻一倍增加 celebrationSparkStep 个
+	celebrationSparkStep  = 4
+	celebrationSparkRange = 110 // 火花飞出的最远距离
+)
+
+// outcomeFade 结束和胜利界面共用的延迟淡入，按实际经过的时间推进
+type outcomeFade struct {
+	elapsed    time.Duration // 界面打开后经过的时间，暂停期间不计
+	lastUpdate time.Time
+}
+
+// 推进一帧，返回淡入是否已经完成，完成前不响应输入
+func (f *outcomeFade) update() bool {
+	f.elapsed += frameDeltaSince(&f.lastUpdate)
+	return f.elapsed >= outcomeDelay+outcomeFadeTime
+}
+
+// 淡入进度 (0.0 - 1.0)
+func (f *outcomeFade) progress() float64 {
+	p := float64(f.elapsed-outcomeDelay) / float64(outcomeFadeTime)
+	if p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
+	}
+	return p
+}
+
+// 按淡入进度绘制遮罩，返回文字应使用的颜色
+func (f *outcomeFade) drawShade(screen *ebiten.Image) color.Color {
+	p := f.progress()
+	drawScreenShade(screen, color.NRGBA{0, 0, 0, uint8(outcomeShadeAlpha * p)})
+	return color.NRGBA{255, 255, 255, uint8(255 * p)}
+}
+
+// 游戏结束场景：本局小结，可以重新开始、撤销最后一步或查看赛后分析
+//
+// 手柄A键执行选中项，Y键再来一局，B键撤销。
+type gameOverScene struct {
+	fade outcomeFade
+	menu menu
+}
+
+// 打开游戏结束界面，没有可撤销的步时不显示撤销
+func (g *Game) openGameOver() {
+	s := &gameOverScene{}
+	s.menu.items = []menuItem{{"outcome.try_again", func(g *Game) {
+		g.popScene()
+		g.requestReset(nil)
+	}}}
+	if len(g.undoStack) > 0 {
+		s.menu.items = append(s.menu.items, menuItem{"outcome.undo", func(g *Game) {
+			g.popScene()
+			g.undo()
+		}})
+	}
+	s.menu.items = append(s.menu.items, menuItem{"outcome.analyze", func(g *Game) {
+		g.openAnalysis(g.resultRecord())
+	}})
+	g.pushScene(s)
+}
+
+func (s *gameOverScene) menuTop() float64 {
+	return layout.canvasHeight/2 - 15
+}
+
+// 暂停菜单或设置中重新开始、读档之后，本局已不再结束，界面随之关闭
+func (s *gameOverScene) stale(g *Game) bool {
+	return !g.gameOver
+}
+
+func (s *gameOverScene) update(g *Game) {
+	if s.stale(g) {
+		g.popScene()
+		return
+	}
+	if !s.fade.update() {
+		return
+	}
+
+	switch {
+	case g.keys.justPressed(ActionPause):
+		g.openPause()
+		return
+	case g.actionPressed(ActionReset):
+		s.menu.items[0].run(g)
+		return
+	case g.actionPressed(ActionUndo) && len(g.undoStack) > 0:
+		s.menu.items[1].run(g)
+		return
+	case g.keys.justPressed(ActionAnalyze):
+		g.openAnalysis(g.resultRecord())
+		return
+	}
+	s.menu.update(g, s.menuTop())
+}
+
+func (s *gameOverScene) draw(g *Game, screen *ebiten.Image) {
+	if s.stale(g) {
+		return
+	}
+	clr := s.fade.drawShade(screen)
+	centerX, centerY := layout.canvasWidth/2, layout.canvasHeight/2
+	drawTextCentered(screen, tr("overlay.game_over"), titleFont, centerX, centerY-110, clr)
+	drawTextCentered(screen, tr("outcome.summary_score", g.score, g.board.maxTile()), boldFont, centerX, centerY-70, clr)
+	drawTextCentered(screen, tr("outcome.summary_moves", len(g.record.Moves), formatPlayTime(g.record.PlayTime)), boldFont, centerX, centerY-45, clr)
+
+	// 淡入完成后才显示可以操作的菜单
+	if s.fade.progress() >= 1 {
+		s.menu.draw(screen, s.menuTop())
+	}
+}
+
+func (s *gameOverScene) overlay() bool { return true }
+
+// 里程碑庆祝场景：达到胜利目标及之后的每次翻倍时显示，可以继续游戏或开始新游戏，点击菜单以外的地方也会继续
+//
+// 手柄A键继续，Y键开始新游戏，B键撤销达到里程碑的一步。
+type winScene struct {
+	milestone int
+	fade      outcomeFade // 延迟淡入，经过的时间也用于庆祝动画
+	menu      menu
+}
+
+// 打开当前里程碑的庆祝界面
+func (g *Game) openWin() {
+	s := &winScene{milestone: g.milestone}
+	s.menu.items = []menuItem{
+		{"outcome.continue", func(g *Game) {
+			g.popScene()
+			g.continueAfterWin()
+		}},
+		{"menu.new_game", func(g *Game) {
+			g.popScene()
+			g.requestReset(nil)
+		}},
+	}
+	g.pushScene(s)
+}
+
+func (s *winScene) menuTop() float64 {
+	return layout.canvasHeight/2 + 40
+}
+
+// 重新开始、读档或撤销之后，庆祝的里程碑已不是当前的里程碑，界面随之关闭
+func (s *winScene) stale(g *Game) bool {
+	return !g.showWin || g.milestone != s.milestone
+}
+
+func (s *winScene) update(g *Game) {
+	if s.stale(g) {
+		g.popScene()
+		return
+	}
+	if !s.fade.update() {
+		return
+	}
+
+	switch {
+	case g.keys.justPressed(ActionPause):
+		g.openPause()
+		return
+	case g.actionPressed(ActionContinue):
+		s.menu.items[0].run(g)
+		return
+	case g.actionPressed(ActionReset):
+		s.menu.items[1].run(g)
+		return
+	case g.actionPressed(ActionUndo) && len(g.undoStack) > 0:
+		// 撤销达到里程碑的一步
+		g.popScene()
+		g.undo()
+		return
+	}
+
+	x, y := cursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && s.menu.itemAt(x, y, s.menuTop()) < 0 {
+		s.menu.items[0].run(g)
+		return
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		if x, y := touchPosition(id); s.menu.itemAt(x, y, s.menuTop()) < 0 {
+			s.menu.items[0].run(g)
+			return
+		}
+	}
+	s.menu.update(g, s.menuTop())
+}
+
+func (s *winScene) draw(g *Game, screen *ebiten.Image) {
+	if s.stale(g) {
+		return
+	}
+	clr := s.fade.drawShade(screen)
+	centerX, centerY := layout.canvasWidth/2, layout.canvasHeight/2
+	s.drawCelebration(screen, centerX, centerY-120)
+
+	// 第一个里程碑就是胜利，之后的里程碑显示达到的数值
+	title := tr("overlay.win")
+	if s.milestone > currentRules.winTile {
+		title = tr("outcome.milestone", tileLabel(s.milestone))
+	}
+	drawTextCentered(screen, title, titleFont, centerX, centerY-40, clr)
+	drawTextCentered(screen, tr("overlay.win_hint", g.keyHint(ActionContinue)), boldFont, centerX, centerY+10, clr)
+
+	if s.fade.progress() >= 1 {
+		s.menu.draw(screen, s.menuTop())
+	}
+}
+
+func (s *winScene) overlay() bool { return true }
+
+// 绘制庆祝动画：里程碑方块弹出后轻微跳动，四周不断飞散火花，里程碑越高火花越多
+func (s *winScene) drawCelebration(screen *ebiten.Image, x, y float64) {
+	p := s.fade.progress()
+	if p == 0 {
+		return
+	}
+
+	level := 0
+	for m := s.milestone; m > currentRules.winTile && m > 1; m /= 2 {
+		level++
+	}
+	elapsed := s.fade.elapsed
+	sparks := celebrationSparkBase + celebrationSparkStep*level
+	for i := 0; i < sparks; i++ {
+		// 相邻的火花错开半轮，保证任何时刻都有火花在飞
+		t := float64((elapsed+time.Duration(i%2)*celebrationCycle/2)%celebrationCycle) / float64(celebrationCycle)
+		angle := 2*math.Pi*float64(i)/float64(sparks) + elapsed.Seconds()*celebrationSpin
+		dist := celebrationTileSize/2 + celebrationSparkRange*easeOutQuad(t)
+		clr := color.NRGBA(currentTheme.tileColor(s.milestone << (i % 2)))
+		clr.A = uint8(255 * p * (1 - t))
+		drawCircle(screen, x+dist*math.Cos(angle), y+dist*math.Sin(angle), 2+3*(1-t), clr)
+	}
+
+	// 方块随淡入放大出现，之后轻微跳动
+	size := celebrationTileSize * easeOutQuad(p) * (1 + 0.05*math.Sin(elapsed.Seconds()*celebrationPulse))
+	drawRect(screen, x-size/2, y-size/2, size, size, currentTheme.tileColor(s.milestone))
+	if p < 1 {
+		return
+	}
+	label := tileLabel(s.milestone)
+	face := tileFaceFor(label, int(size))
+	w, h := textSize(face, label)
+	drawText(screen, label, face, x-w/2, y+h/2, currentTheme.tileTextColor(s.milestone))
+}
+
+// 游戏时间的显示，例如 3:07 或 1:02:45
+func formatPlayTime(d time.Duration) string {
+	total := int(d.Seconds())
+	h, m, s := total/3600, total/60%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 回放时每一步之间的间隔
+const replayStepInterval = 400 * time.Millisecond
+
+// 回放场景：从记录的初始局面按顺序重现每一步
+type replayScene struct {
+	record  GameRecord
+	game    *Game // 回放用的棋盘，不播放声音，也不写存档和历史记录
+	step    int
+	paused  bool
+	elapsed time.Duration
+}
+
+// 打开对局回放
+func (g *Game) openReplay(r GameRecord) {
+	setBoardSize(len(r.InitialBoard))
+	replay := newReplayGame(r)
+	replay.config = g.config
+	replay.keys = g.keys
+	g.pushScene(&replayScene{record: r, game: replay})
+}
+
+// 创建从记录的初始局面开始的棋盘，不播放声音，也不写存档和历史记录
+//
+// 调用前需要先按记录设置棋盘尺寸。
+func newReplayGame(r GameRecord) *Game {
+	return &Game{
+		board:             r.InitialBoard.clone(),
+		previousBoard:     newBoard(boardSize),
+		score:             r.InitialScore,
+		bestScore:         r.Score,
+		animations:        []TileAnimation{},
+		lastMoveDirection: -1,
+	}
+}
+
+// 关闭回放，恢复当前游戏的棋盘尺寸
+func (s *replayScene) close(g *Game) {
+	setBoardSize(len(g.board))
+	g.popScene()
+}
+
+// 回放下一步，动画未结束时先结束动画
+func (s *replayScene) stepForward() {
+	if s.step >= len(s.record.Moves) {
+		return
+	}
+	m := s.record.Moves[s.step]
+	s.game.finishAnimation()
+	if m.Spawn != nil {
+		spawn := *m.Spawn
+		s.game.nextSpawn = &spawn
+	}
+	s.game.move(m.Direction)
+	s.step++
+	s.elapsed = 0
+}
+
+func (s *replayScene) update(g *Game) {
+	dt := s.game.frameDelta()
+	s.game.advanceAnimation(dt)
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		s.close(g)
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		s.paused = !s.paused
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		s.stepForward()
+	}
+
+	if s.paused {
+		return
+	}
+	s.elapsed += dt
+	if s.elapsed >= replayStepInterval && !s.game.animating {
+		s.stepForward()
+	}
+}
+
+func (s *replayScene) draw(g *Game, screen *ebiten.Image) {
+	s.game.drawPlayfield(screen)
+
+	status := tr("replay.status", s.step, len(s.record.Moves))
+	if s.paused {
+		status = tr("replay.paused", s.step, len(s.record.Moves))
+	} else if s.step >= len(s.record.Moves) {
+		status = tr("replay.finished")
+	}
+	drawTextCentered(screen, status, boldFont, layout.infoX, float64(layout.buttonsY+14), currentTheme.Text)
+	for i, line := range layout.hintLines(tr("replay.help")) {
+		drawTextCentered(screen, line, scoreFont, layout.infoX, float64(layout.hintY+i*20), currentTheme.Text)
+	}
+}
+
+func (s *replayScene) overlay() bool { return false }
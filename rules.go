@@ -0,0 +1,49 @@
+package main
+
+// ruleVariant 规则变体
+type ruleVariant struct {
+	name       string
+	winTile    int     // 达到该数值时胜利，0 表示没有胜利目标
+	fourChance float64 // 新方块为 4 的概率
+}
+
+// 默认规则变体名称
+const defaultRuleVariant = "classic"
+
+// 内置规则变体
+var ruleVariants = []ruleVariant{
+	{name: "classic", winTile: 2048, fourChance: 0.1},
+	{name: "quick", winTile: 512, fourChance: 0.1},
+	{name: "hard", winTile: 2048, fourChance: 0.4},
+	{name: "endless", winTile: 0, fourChance: 0.1},
+}
+
+// 当前使用的规则
+var currentRules = ruleVariants[0]
+
+// 根据名称查找规则变体，找不到时返回默认规则
+func findRuleVariant(name string) ruleVariant {
+	for _, r := range ruleVariants {
+		if r.name == name {
+			return r
+		}
+	}
+	return ruleVariants[0]
+}
+
+// 方块对应的里程碑：胜利目标及其之后的每次翻倍，未达到胜利目标或没有目标时为 0
+func (r ruleVariant) milestone(tile int) int {
+	if r.winTile == 0 || tile < r.winTile {
+		return 0
+	}
+	m := r.winTile
+	for m*2 <= tile {
+		m *= 2
+	}
+	return m
+}
+
+// 规则的显示名称
+func (r ruleVariant) label() string {
+	return tr("rules." + r.name)
+}
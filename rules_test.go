@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestRuleMilestone(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		tile  int
+		want  int
+	}{
+		{"未达到胜利目标", "classic", 1024, 0},
+		{"刚好达到胜利目标", "classic", 2048, 2048},
+		{"胜利目标之后的翻倍", "classic", 8192, 8192},
+		{"快速模式的目标更低", "quick", 1024, 1024},
+		{"无尽模式没有里程碑", "endless", 1 << 20, 0},
+		{"未知规则按经典规则", "unknown", 4096, 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findRuleVariant(tt.rules).milestone(tt.tile); got != tt.want {
+				t.Fatalf("milestone(%d) = %d, want %d", tt.tile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWinMilestones(t *testing.T) {
+	saved := currentRules
+	defer func() { currentRules = saved }()
+	currentRules = findRuleVariant("classic")
+
+	// 按顺序达到的最大方块，showWin 为 false 表示上一个里程碑已选择继续
+	steps := []struct {
+		name      string
+		maxTile   int
+		continued bool
+		milestone int
+		showWin   bool
+	}{
+		{"未达到胜利目标", 1024, false, 0, false},
+		{"达到胜利目标", 2048, false, 2048, true},
+		{"继续后同一里程碑不再显示", 2048, true, 2048, false},
+		{"下一次翻倍", 4096, true, 4096, true},
+		{"继续后跳过一个里程碑", 16384, true, 16384, true},
+		{"最大方块变小时保留已达到的里程碑", 1024, true, 16384, false},
+	}
+
+	g := &Game{}
+	for _, st := range steps {
+		t.Run(st.name, func(t *testing.T) {
+			if st.continued {
+				g.showWin = false
+			}
+			g.board = Board{{st.maxTile, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+			g.checkWin()
+			if g.milestone != st.milestone || g.showWin != st.showWin || g.win != (st.milestone > 0) {
+				t.Fatalf("checkWin() milestone = %d, showWin = %v, win = %v, want %d, %v, %v",
+					g.milestone, g.showWin, g.win, st.milestone, st.showWin, st.milestone > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// 切换场景时淡入的帧数
+const sceneTransitionFrames = 12
+
+// scene 游戏中的一个界面，Update 和 Draw 交给场景栈顶的场景处理
+type scene interface {
+	update(g *Game)
+	draw(g *Game, screen *ebiten.Image)
+	// 覆盖层场景绘制在下层场景之上，例如暂停菜单
+	overlay() bool
+}
+
+// 当前处理输入的场景
+func (g *Game) currentScene() scene {
+	if len(g.scenes) == 0 {
+		return playScene{}
+	}
+	return g.scenes[len(g.scenes)-1]
+}
+
+// 打开新场景，返回时回到当前场景
+func (g *Game) pushScene(s scene) {
+	g.scenes = append(g.scenes, s)
+	g.startTransition(s)
+}
+
+// 关闭当前场景，关闭覆盖层时下层场景不淡入
+func (g *Game) popScene() {
+	top := g.currentScene()
+	if len(g.scenes) > 0 {
+		g.scenes = g.scenes[:len(g.scenes)-1]
+	}
+	if !top.overlay() {
+		g.transition = sceneTransitionFrames
+	}
+}
+
+// 清空场景栈，只保留指定的场景
+func (g *Game) replaceScenes(s scene) {
+	g.scenes = []scene{s}
+	g.startTransition(s)
+}
+
+// 切换到完整界面时淡入，打开或关闭覆盖层时不淡入
+func (g *Game) startTransition(s scene) {
+	if !s.overlay() {
+		g.transition = sceneTransitionFrames
+	}
+}
+
+// 更新当前场景
+func (g *Game) updateScenes() {
+	if g.transition > 0 {
+		g.transition--
+	}
+
+	// 窗口失去焦点时自动暂停
+	focused := ebiten.IsFocused()
+	if g.focused && !focused {
+		g.pauseOnFocusLost()
+	}
+	g.focused = focused
+
+	// 手柄在任何场景中都要读取，菜单和对话框也可以用手柄操作
+	g.updateGamepads()
+
+	// 不在游戏场景时停止计时，返回后从零开始计算帧间隔，动画不会跳过
+	if _, ok := g.currentScene().(playScene); !ok {
+		g.lastUpdate = time.Time{}
+	}
+
+	g.currentScene().update(g)
+}
+
+// 失去焦点时暂停游戏或回放
+func (g *Game) pauseOnFocusLost() {
+	switch s := g.currentScene().(type) {
+	case playScene:
+		g.openPause()
+	case *replayScene:
+		s.paused = true
+	}
+}
+
+// 从最上层的完整界面开始，依次绘制其上的覆盖层
+func (g *Game) drawScenes(screen *ebiten.Image) {
+	if len(g.scenes) == 0 {
+		playScene{}.draw(g, screen)
+		return
+	}
+
+	bottom := len(g.scenes) - 1
+	for bottom > 0 && g.scenes[bottom].overlay() {
+		bottom--
+	}
+	for _, s := range g.scenes[bottom:] {
+		s.draw(g, screen)
+	}
+
+	// 切换场景后从背景色淡入
+	if g.transition > 0 {
+		bg := currentTheme.Background
+		alpha := uint8(255 * g.transition / sceneTransitionFrames)
+		drawScreenShade(screen, color.NRGBA{bg.R, bg.G, bg.B, alpha})
+	}
+}
+
+// 游戏场景
+type playScene struct{}
+
+func (playScene) update(g *Game)                     { g.updatePlaying() }
+func (playScene) draw(g *Game, screen *ebiten.Image) { g.drawPlaying(screen) }
+func (playScene) overlay() bool                      { return false }
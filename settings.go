@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 设置界面布局
+const (
+	settingsListTop      = 100 // 设置列表的起始纵坐标
+	settingsMaxRowHeight = 40
+)
+
+// 可选的动画速度，0 表示关闭动画
+var animationSpeeds = []float64{0, 0.5, 1, 1.5, 2, 3}
+
+// 方块数字显示方式的顺序
+var tileNotations = []string{TileNotationDecimal, TileNotationExponent, TileNotationCompact}
+
+// settingItem 设置界面中的一项
+type settingItem struct {
+	key    string                  // 名称的消息键
+	value  func(g *Game) string    // 当前值的显示文字
+	change func(g *Game, step int) // 向前或向后切换，立即生效并保存
+}
+
+// 设置项列表
+var settingItems = []settingItem{
+	{"settings.theme", settingTheme, (*Game).stepTheme},
+	{"settings.language", settingLanguage, (*Game).stepLanguage},
+	{"settings.animation_speed", settingAnimationSpeed, (*Game).stepAnimationSpeed},
+	{"settings.volume", settingVolume, (*Game).stepVolume},
+	{"settings.board_size", settingBoardSize, (*Game).stepBoardSize},
+	{"settings.rules", settingRules, (*Game).stepRules},
+	{"settings.key_preset", settingKeyPreset, (*Game).cycleKeyPreset},
+	{"settings.tile_notation", settingTileNotation, (*Game).stepTileNotation},
+	{"settings.training", settingTraining, (*Game).stepTraining},
+}
+
+// 设置界面状态
+type settingsScreen struct {
+	selected int
+}
+
+// 打开设置界面
+func (g *Game) openSettings() {
+	g.settings = settingsScreen{}
+	g.pushScene(settingsScene{})
+}
+
+// 设置场景
+type settingsScene struct{}
+
+func (settingsScene) update(g *Game)                     { g.updateSettings() }
+func (settingsScene) draw(g *Game, screen *ebiten.Image) { g.drawSettings(screen) }
+func (settingsScene) overlay() bool                      { return false }
+
+// 设置列表的行高，画布较矮时（横屏）压缩行距
+func settingsRowHeight() int {
+	h := int(layout.canvasHeight-settingsListTop-30) / len(settingItems)
+	if h > settingsMaxRowHeight {
+		h = settingsMaxRowHeight
+	}
+	return h
+}
+
+// 处理设置界面的输入
+func (g *Game) updateSettings() {
+	s := &g.settings
+	n := len(settingItems)
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		s.selected = (s.selected + n - 1) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		s.selected = (s.selected + 1) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		settingItems[s.selected].change(g, -1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight),
+		inpututil.IsKeyJustPressed(ebiten.KeyEnter),
+		inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		settingItems[s.selected].change(g, 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape), g.keys.justPressed(ActionSettings):
+		g.popScene()
+		return
+	}
+
+	// 滚轮切换选中项的值，光标不必在列表上
+	if _, dy := ebiten.Wheel(); dy > 0 {
+		settingItems[s.selected].change(g, 1)
+	} else if dy < 0 {
+		settingItems[s.selected].change(g, -1)
+	}
+
+	// 鼠标点击选中，再次点击切换到下一个值，右键切换到上一个值
+	_, y := cursorPosition()
+	row := -1
+	if y >= settingsListTop {
+		row = (y - settingsListTop) / settingsRowHeight()
+	}
+	if row < 0 || row >= n {
+		return
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if row == s.selected {
+			settingItems[row].change(g, 1)
+		} else {
+			s.selected = row
+		}
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		s.selected = row
+		settingItems[row].change(g, -1)
+	}
+}
+
+// 在列表中循环移动下标
+func stepIndex(current, step, n int) int {
+	return ((current+step)%n + n) % n
+}
+
+// 切换界面语言
+func (g *Game) stepLanguage(step int) {
+	current := 0
+	for i, lang := range supportedLanguages {
+		if lang == currentLanguage {
+			current = i
+		}
+	}
+	lang := supportedLanguages[stepIndex(current, step, len(supportedLanguages))]
+	setLanguage(lang)
+	ebiten.SetWindowTitle(tr("window.title"))
+
+	g.config.Language = lang
+	g.saveConfig()
+}
+
+// 当前动画速度在可选列表中的位置
+func (g *Game) animationSpeedIndex() int {
+	if !g.config.AnimationsEnabled {
+		return 0
+	}
+	best := 1
+	for i := 1; i < len(animationSpeeds); i++ {
+		if math.Abs(animationSpeeds[i]-g.config.AnimationSpeed) < math.Abs(animationSpeeds[best]-g.config.AnimationSpeed) {
+			best = i
+		}
+	}
+	return best
+}
+
+// 切换动画速度，速度为 0 时关闭动画
+func (g *Game) stepAnimationSpeed(step int) {
+	speed := animationSpeeds[stepIndex(g.animationSpeedIndex(), step, len(animationSpeeds))]
+	g.config.AnimationsEnabled = speed > 0
+	if speed > 0 {
+		g.config.AnimationSpeed = speed
+	} else {
+		g.finishAnimation()
+	}
+	g.saveConfig()
+}
+
+// 调整总音量，到达两端时不再循环
+func (g *Game) stepVolume(step int) {
+	g.setVolume(g.config.Volume + float64(step)*volumeStep)
+}
+
+// 切换棋盘尺寸，以新尺寸开始新游戏；有未结束的对局时先确认，取消后新尺寸在下一局生效
+func (g *Game) stepBoardSize(step int) {
+	size := g.config.BoardSize
+	if !validBoardSize(size) {
+		size = defaultBoardSize
+	}
+	count := maxBoardSize - minBoardSize + 1
+	g.config.BoardSize = minBoardSize + stepIndex(size-minBoardSize, step, count)
+	g.saveConfig()
+
+	g.requestReset(func(g *Game) {
+		g.showMessage(tr("msg.new_board", boardSize, boardSize), 60)
+	})
+}
+
+// 切换规则变体，当前对局仍按原来的规则进行，从下一局开始生效
+func (g *Game) stepRules(step int) {
+	selected := findRuleVariant(g.config.RuleVariant).name
+	current := 0
+	for i, r := range ruleVariants {
+		if r.name == selected {
+			current = i
+		}
+	}
+	g.config.RuleVariant = ruleVariants[stepIndex(current, step, len(ruleVariants))].name
+	g.saveConfig()
+
+	if g.config.RuleVariant != currentRules.name {
+		g.showMessage(tr("msg.rules_next_game"), 90)
+	}
+}
+
+// 开关训练提示
+func (g *Game) stepTraining(step int) {
+	g.config.ShowTraining = !g.config.ShowTraining
+	g.saveConfig()
+}
+
+// 切换方块数字的显示方式
+func (g *Game) stepTileNotation(step int) {
+	current := 0
+	for i, n := range tileNotations {
+		if n == currentTileNotation {
+			current = i
+		}
+	}
+	setTileNotation(tileNotations[stepIndex(current, step, len(tileNotations))])
+
+	g.config.TileNotation = currentTileNotation
+	g.saveConfig()
+}
+
+func settingTheme(g *Game) string {
+	return currentTheme.label()
+}
+
+func settingLanguage(g *Game) string {
+	return tr("language.name")
+}
+
+func settingAnimationSpeed(g *Game) string {
+	speed := animationSpeeds[g.animationSpeedIndex()]
+	if speed == 0 {
+		return tr("settings.off")
+	}
+	return fmt.Sprintf("%gx", speed)
+}
+
+func settingVolume(g *Game) string {
+	return fmt.Sprintf("%d%%", int(math.Round(g.config.Volume*100)))
+}
+
+func settingBoardSize(g *Game) string {
+	size := g.config.BoardSize
+	if !validBoardSize(size) {
+		size = defaultBoardSize
+	}
+	return fmt.Sprintf("%dx%d", size, size)
+}
+
+func settingRules(g *Game) string {
+	return findRuleVariant(g.config.RuleVariant).label()
+}
+
+func settingKeyPreset(g *Game) string {
+	return findKeyPreset(g.config.KeyPreset).label()
+}
+
+func settingTraining(g *Game) string {
+	if g.config.ShowTraining {
+		return tr("settings.on")
+	}
+	return tr("settings.off")
+}
+
+func settingTileNotation(g *Game) string {
+	return tr("notation." + currentTileNotation)
+}
+
+// 绘制设置界面
+func (g *Game) drawSettings(screen *ebiten.Image) {
+	s := &g.settings
+
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	drawTextCentered(screen, tr("settings.title"), titleFont, centerX, 60, currentTheme.Text)
+
+	rowHeight := settingsRowHeight()
+	for i, item := range settingItems {
+		y := float64(settingsListTop + i*rowHeight)
+
+		col := currentTheme.Text
+		value := item.value(g)
+		if i == s.selected {
+			drawRect(screen, boardMargin, y, layout.canvasWidth-2*boardMargin, float64(rowHeight-4), currentTheme.Board)
+			col = currentTheme.TextLight
+			value = "< " + value + " >"
+		}
+
+		baseline := y + float64(rowHeight)/2 + 4
+		drawText(screen, tr(item.key), boldFont, boardMargin+10, baseline, col)
+		valueWidth, _ := textSize(boldFont, value)
+		drawText(screen, value, boldFont, layout.canvasWidth-boardMargin-10-valueWidth, baseline, col)
+	}
+
+	drawTextCentered(screen, tr("settings.help"), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// 统计界面布局
+const (
+	statsTop            = 116 // 图表区域的起始纵坐标
+	statsChartGap       = 20
+	statsMaxScorePoints = 50 // 分数走势最多显示的对局数
+)
+
+// 方向的显示符号，按 DirectionUp、DirectionRight、DirectionDown、DirectionLeft 排列
+var directionSymbols = [4]string{"↑", "→", "↓", "←"}
+
+// gameStats 根据历史记录汇总的统计数据
+type gameStats struct {
+	games      int
+	scores     []float64 // 最近对局的得分，按时间顺序排列
+	avgScore   int
+	avgMoves   float64
+	maxTiles   map[int]int // 各最大方块的对局数
+	milestone  int         // 达到的最高里程碑
+	milestones int         // 达到过里程碑的对局数
+	merges     map[int]int // 合并出各数值方块的次数
+	directions [4]int      // 各方向的移动次数
+}
+
+// 汇总历史记录，练习局不计入；合并次数通过在棋盘副本上重放每一局得到
+func collectStats(records []GameRecord) gameStats {
+	s := gameStats{
+		maxTiles: map[int]int{},
+		merges:   map[int]int{},
+	}
+
+	totalScore, totalMoves := 0, 0
+	for _, r := range records {
+		if r.Practice {
+			continue
+		}
+		s.games++
+		s.scores = append(s.scores, float64(r.Score))
+		totalScore += r.Score
+		totalMoves += len(r.Moves)
+		s.maxTiles[r.MaxTile]++
+
+		// 旧记录没有里程碑，按规则和最大方块推算
+		milestone := r.Milestone
+		if milestone == 0 && r.Won {
+			milestone = findRuleVariant(r.Rules).milestone(r.MaxTile)
+		}
+		if milestone > 0 {
+			s.milestones++
+		}
+		if milestone > s.milestone {
+			s.milestone = milestone
+		}
+
+		board := r.InitialBoard.clone()
+		for _, m := range r.Moves {
+			if !validDirection(m.Direction) {
+				break
+			}
+			after, _, moved := slideBoard(board, m.Direction)
+			if !moved {
+				// 记录与棋盘不一致，后面的步无法统计
+				break
+			}
+			s.directions[m.Direction]++
+			for _, v := range slideMerges(board, m.Direction) {
+				s.merges[v]++
+			}
+			m.placeSpawn(after)
+			board = after
+		}
+	}
+
+	if s.games > 0 {
+		s.avgScore = totalScore / s.games
+		s.avgMoves = float64(totalMoves) / float64(s.games)
+	}
+	if len(s.scores) > statsMaxScorePoints {
+		s.scores = s.scores[len(s.scores)-statsMaxScorePoints:]
+	}
+	return s
+}
+
+// chartBar 柱状图中的一根柱子
+type chartBar struct {
+	label string
+	value float64
+	tile  int // 按方块数值取颜色，0 表示使用默认颜色
+}
+
+// 按方块数值从小到大排列的柱子
+func tileBars(counts map[int]int) []chartBar {
+	values := make([]int, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+
+	bars := make([]chartBar, len(values))
+	for i, v := range values {
+		bars[i] = chartBar{tileLabel(v), float64(counts[v]), v}
+	}
+	return bars
+}
+
+// 统计界面场景
+type statsScene struct {
+	stats gameStats
+}
+
+// 打开统计界面
+func (g *Game) openStats() {
+	g.pushScene(&statsScene{stats: collectStats(g.history)})
+}
+
+func (s *statsScene) update(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		g.popScene()
+	}
+}
+
+func (s *statsScene) draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(currentTheme.Background)
+	centerX := layout.canvasWidth / 2
+	drawTextCentered(screen, tr("stats.title"), titleFont, centerX, 60, currentTheme.Text)
+	drawTextCentered(screen, tr("stats.help"), scoreFont, centerX, layout.canvasHeight-12, currentTheme.Text)
+
+	st := &s.stats
+	if st.games == 0 {
+		drawTextCentered(screen, tr("stats.empty"), boldFont, centerX, layout.canvasHeight/2, currentTheme.Text)
+		return
+	}
+	drawTextCentered(screen, tr("stats.summary", st.games, g.bestScore, st.avgScore, st.avgMoves), scoreFont, centerX, 88, currentTheme.Text)
+	milestone := "-"
+	if st.milestone > 0 {
+		milestone = tileLabel(st.milestone)
+	}
+	drawTextCentered(screen, tr("stats.milestones", milestone, st.milestones), scoreFont, centerX, 106, currentTheme.Text)
+
+	// 两行两列排列四个图表
+	w := (layout.canvasWidth - 3*statsChartGap) / 2
+	h := (layout.canvasHeight - statsTop - 30 - statsChartGap) / 2
+	left, right := float64(statsChartGap), 2*statsChartGap+w
+	bottom := statsTop + h + statsChartGap
+
+	drawLineChart(screen, tr("stats.scores"), st.scores, left, statsTop, w, h)
+	drawBarChart(screen, tr("stats.max_tiles"), tileBars(st.maxTiles), right, statsTop, w, h)
+	drawBarChart(screen, tr("stats.merges"), tileBars(st.merges), left, bottom, w, h)
+
+	directions := make([]chartBar, len(st.directions))
+	for i, n := range st.directions {
+		directions[i] = chartBar{directionSymbols[i], float64(n), 0}
+	}
+	drawBarChart(screen, tr("stats.directions"), directions, right, bottom, w, h)
+}
+
+func (s *statsScene) overlay() bool { return false }
+
+// 图表的边框、标题和最大值，返回绘图区域
+func drawChartFrame(screen *ebiten.Image, title string, maxValue float64, x, y, w, h float64) (px, py, pw, ph float64) {
+	drawRect(screen, x, y, w, h, currentTheme.Board)
+	drawText(screen, title, scoreFont, x+8, y+18, currentTheme.TextLight)
+	maxLabel := compactNumber(int(maxValue))
+	maxWidth, _ := textSize(scoreFont, maxLabel)
+	drawText(screen, maxLabel, scoreFont, x+w-8-maxWidth, y+18, currentTheme.TextLight)
+	return x + 10, y + 28, w - 20, h - 28 - 22
+}
+
+// 绘制柱状图，柱子太密时隔几根显示一个标签
+func drawBarChart(screen *ebiten.Image, title string, bars []chartBar, x, y, w, h float64) {
+	maxValue := 0.0
+	for _, b := range bars {
+		maxValue = math.Max(maxValue, b.value)
+	}
+	px, py, pw, ph := drawChartFrame(screen, title, maxValue, x, y, w, h)
+	if len(bars) == 0 || maxValue == 0 {
+		return
+	}
+
+	slot := pw / float64(len(bars))
+	labelWidth := 0.0
+	for _, b := range bars {
+		lw, _ := textSize(scoreFont, b.label)
+		labelWidth = math.Max(labelWidth, lw)
+	}
+	labelStep := int(math.Ceil((labelWidth + 4) / slot))
+
+	for i, b := range bars {
+		barHeight := ph * b.value / maxValue
+		barX := px + slot*float64(i) + slot*0.15
+		clr := currentTheme.TextLight
+		if b.tile > 0 {
+			clr = currentTheme.tileColor(b.tile)
+		}
+		drawRect(screen, barX, py+ph-barHeight, slot*0.7, barHeight, clr)
+		if i%labelStep == 0 {
+			drawTextCentered(screen, b.label, scoreFont, px+slot*(float64(i)+0.5), y+h-6, currentTheme.TextLight)
+		}
+	}
+}
+
+// 绘制折线图，数值从左到右按顺序排列
+func drawLineChart(screen *ebiten.Image, title string, values []float64, x, y, w, h float64) {
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	px, py, pw, ph := drawChartFrame(screen, title, maxValue, x, y, w, h)
+	if len(values) == 0 || maxValue == 0 {
+		return
+	}
+
+	point := func(i int) (float64, float64) {
+		t := 0.5
+		if len(values) > 1 {
+			t = float64(i) / float64(len(values)-1)
+		}
+		return px + pw*t, py + ph - ph*values[i]/maxValue
+	}
+
+	clr := currentTheme.TextLight
+	drawLine(screen, px, py+ph, px+pw, py+ph, 1, clr)
+	for i := range values {
+		x1, y1 := point(i)
+		if i > 0 {
+			x0, y0 := point(i - 1)
+			drawLine(screen, x0, y0, x1, y1, 2, clr)
+		}
+		drawCircle(screen, x1, y1, 3, clr)
+	}
+}
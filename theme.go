@@ -0,0 +1,286 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 用户主题目录，其中的 JSON 文件会与内置主题一起加载，同名时覆盖内置主题
+const userThemeDir = "themes"
+
+// 内置主题
+//
+//go:embed themes/*.json
+var builtinThemeFS embed.FS
+
+// 内置主题的顺序
+var builtinThemeNames = []string{"light", "dark", "high-contrast", "colorblind"}
+
+// 当前使用的主题
+var currentTheme = defaultTheme()
+
+// hexColor 以 "#rrggbb" 或 "#rrggbbaa" 表示的颜色
+type hexColor color.RGBA
+
+// 解析十六进制颜色
+func (c *hexColor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	s = strings.TrimPrefix(s, "#")
+	var r, g, b, a uint8 = 0, 0, 0, 255
+	switch len(s) {
+	case 6:
+		_, err := fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
+		if err != nil {
+			return fmt.Errorf("无效的颜色 %q", s)
+		}
+	case 8:
+		_, err := fmt.Sscanf(s, "%02x%02x%02x%02x", &r, &g, &b, &a)
+		if err != nil {
+			return fmt.Errorf("无效的颜色 %q", s)
+		}
+	default:
+		return fmt.Errorf("无效的颜色 %q", s)
+	}
+
+	*c = hexColor{r, g, b, a}
+	return nil
+}
+
+// Theme 配色主题
+type Theme struct {
+	Name          string
+	Label         string
+	Background    color.RGBA
+	Board         color.RGBA
+	EmptyTile     color.RGBA
+	Text          color.RGBA // 背景上的文字
+	TextLight     color.RGBA // 面板和按钮上的文字
+	TileTextDark  color.RGBA // 浅色方块上的数字
+	TileTextLight color.RGBA // 深色方块上的数字
+	DarkTextMax   int        // 不超过该数值的方块使用深色数字
+	Tiles         map[int]color.RGBA
+	Overflow      []color.RGBA // 超出已定义方块后循环使用的渐变色
+	maxTile       int          // 已定义颜色的最大方块
+}
+
+// 主题文件格式
+type themeFile struct {
+	Name          string           `json:"name"`
+	Label         string           `json:"label"`
+	Background    hexColor         `json:"background"`
+	Board         hexColor         `json:"board"`
+	EmptyTile     hexColor         `json:"empty_tile"`
+	Text          hexColor         `json:"text"`
+	TextLight     hexColor         `json:"text_light"`
+	TileTextDark  hexColor         `json:"tile_text_dark"`
+	TileTextLight hexColor         `json:"tile_text_light"`
+	DarkTextMax   int              `json:"dark_text_max"`
+	Tiles         map[int]hexColor `json:"tiles"`
+	Overflow      []hexColor       `json:"overflow"`
+}
+
+// 解析主题文件
+func parseTheme(data []byte) (*Theme, error) {
+	var f themeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Name == "" {
+		return nil, fmt.Errorf("主题缺少名称")
+	}
+	if len(f.Tiles) == 0 {
+		return nil, fmt.Errorf("主题 %s 没有定义方块颜色", f.Name)
+	}
+
+	t := &Theme{
+		Name:          f.Name,
+		Label:         f.Label,
+		Background:    color.RGBA(f.Background),
+		Board:         color.RGBA(f.Board),
+		EmptyTile:     color.RGBA(f.EmptyTile),
+		Text:          color.RGBA(f.Text),
+		TextLight:     color.RGBA(f.TextLight),
+		TileTextDark:  color.RGBA(f.TileTextDark),
+		TileTextLight: color.RGBA(f.TileTextLight),
+		DarkTextMax:   f.DarkTextMax,
+		Tiles:         map[int]color.RGBA{},
+	}
+	if t.Label == "" {
+		t.Label = t.Name
+	}
+	for value, c := range f.Tiles {
+		t.Tiles[value] = color.RGBA(c)
+		if value > t.maxTile {
+			t.maxTile = value
+		}
+	}
+	for _, c := range f.Overflow {
+		t.Overflow = append(t.Overflow, color.RGBA(c))
+	}
+	if len(t.Overflow) == 0 {
+		t.Overflow = []color.RGBA{t.Tiles[t.maxTile], t.TileTextDark}
+	}
+
+	return t, nil
+}
+
+// 默认主题，内置主题文件损坏时也能正常显示
+func defaultTheme() *Theme {
+	data, err := builtinThemeFS.ReadFile("themes/light.json")
+	if err == nil {
+		if t, err := parseTheme(data); err == nil {
+			return t
+		}
+	}
+	log.Printf("无法加载默认主题: %v", err)
+
+	return &Theme{
+		Name:          "light",
+		Label:         "light",
+		Background:    color.RGBA{250, 248, 239, 255},
+		Board:         color.RGBA{187, 173, 160, 255},
+		EmptyTile:     color.RGBA{205, 193, 180, 255},
+		Text:          color.RGBA{119, 110, 101, 255},
+		TextLight:     color.RGBA{249, 246, 242, 255},
+		TileTextDark:  color.RGBA{119, 110, 101, 255},
+		TileTextLight: color.RGBA{249, 246, 242, 255},
+		DarkTextMax:   4,
+		Tiles:         map[int]color.RGBA{2: {238, 228, 218, 255}},
+		Overflow:      []color.RGBA{{237, 194, 46, 255}, {60, 58, 50, 255}},
+		maxTile:       2,
+	}
+}
+
+// 加载所有主题：先按固定顺序加载内置主题，再加载用户主题目录
+func loadThemes() []*Theme {
+	var themes []*Theme
+	index := map[string]int{}
+
+	add := func(t *Theme) {
+		if i, ok := index[t.Name]; ok {
+			themes[i] = t
+			return
+		}
+		index[t.Name] = len(themes)
+		themes = append(themes, t)
+	}
+
+	for _, name := range builtinThemeNames {
+		data, err := builtinThemeFS.ReadFile("themes/" + name + ".json")
+		if err != nil {
+			log.Printf("读取内置主题失败: %v", err)
+			continue
+		}
+		t, err := parseTheme(data)
+		if err != nil {
+			log.Printf("解析内置主题 %s 失败: %v", name, err)
+			continue
+		}
+		add(t)
+	}
+
+	files, err := ioutil.ReadDir(userThemeDir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("读取主题目录失败: %v", err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(userThemeDir, file.Name()))
+		if err != nil {
+			log.Printf("读取主题文件失败: %v", err)
+			continue
+		}
+		t, err := parseTheme(data)
+		if err != nil {
+			log.Printf("解析主题文件 %s 失败: %v", file.Name(), err)
+			continue
+		}
+		add(t)
+	}
+
+	if len(themes) == 0 {
+		themes = append(themes, defaultTheme())
+	}
+	return themes
+}
+
+// 获取方块颜色，超出已定义范围的方块使用循环渐变色
+func (t *Theme) tileColor(value int) color.RGBA {
+	if c, ok := t.Tiles[value]; ok {
+		return c
+	}
+	if value <= t.maxTile {
+		return t.EmptyTile
+	}
+
+	// 每大一级在渐变色之间前进半步，循环使用以支持任意大的方块
+	steps := tileExponent(value) - tileExponent(t.maxTile)
+	if steps < 1 {
+		steps = 1
+	}
+	pos := float64(steps-1) * 0.5
+	i := int(pos) % len(t.Overflow)
+	frac := pos - math.Floor(pos)
+	return lerpColor(t.Overflow[i], t.Overflow[(i+1)%len(t.Overflow)], frac)
+}
+
+// 主题的显示名称，内置主题使用当前语言的名称
+func (t *Theme) label() string {
+	return trOr("theme."+t.Name, t.Label)
+}
+
+// 获取方块数字的颜色
+func (t *Theme) tileTextColor(value int) color.RGBA {
+	if value <= t.DarkTextMax {
+		return t.TileTextDark
+	}
+	return t.TileTextLight
+}
+
+// 切换到下一个主题
+func (g *Game) cycleTheme() {
+	g.stepTheme(1)
+	g.showMessage(tr("msg.theme", currentTheme.label()), 60)
+}
+
+// 按顺序前后切换主题并保存到配置
+func (g *Game) stepTheme(delta int) {
+	current := 0
+	for i, t := range g.themes {
+		if t.Name == currentTheme.Name {
+			current = i
+		}
+	}
+	n := len(g.themes)
+	currentTheme = g.themes[((current+delta)%n+n)%n]
+
+	g.config.Theme = currentTheme.Name
+	g.saveConfig()
+}
+
+// 根据名称选择主题，找不到时使用第一个主题
+func (g *Game) selectTheme(name string) {
+	currentTheme = g.themes[0]
+	for _, t := range g.themes {
+		if t.Name == name {
+			currentTheme = t
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+)
+
+// 方块数字的显示方式
+const (
+	TileNotationDecimal  = "decimal"  // 完整数字，例如 131072
+	TileNotationExponent = "exponent" // 指数，例如 2^17
+	TileNotationCompact  = "compact"  // 缩写，例如 131k
+)
+
+// 数值超过该值时才使用指数或缩写显示，较小的方块始终显示完整数字
+const tileNotationThreshold = 9999
+
+// 方块数字的最小字号（逻辑单位）
+const tileFontMinSize = 12.0
+
+// 方块数字左右至少保留的边距
+const tileTextPadding = 8
+
+// 各字符数对应的方块字号（逻辑单位），字符越多字号越小
+var tileFontSizes = []float64{36, 36, 32, 26, 22, 18, 16}
+
+// 当前的方块数字显示方式
+var currentTileNotation = TileNotationDecimal
+
+// 设置方块数字的显示方式，未知的方式使用完整数字
+func setTileNotation(notation string) {
+	switch notation {
+	case TileNotationDecimal, TileNotationExponent, TileNotationCompact:
+		currentTileNotation = notation
+	default:
+		log.Printf("未知的方块数字显示方式: %s", notation)
+		currentTileNotation = TileNotationDecimal
+	}
+}
+
+// 方块上显示的文字
+func tileLabel(value int) string {
+	if value <= tileNotationThreshold {
+		return strconv.Itoa(value)
+	}
+
+	switch currentTileNotation {
+	case TileNotationExponent:
+		if exp := tileExponent(value); exp > 0 {
+			return fmt.Sprintf("2^%d", exp)
+		}
+	case TileNotationCompact:
+		return compactNumber(value)
+	}
+	return strconv.Itoa(value)
+}
+
+// 缩写显示数字，保留三位有效数字，例如 16.4k、131k、1.05M
+func compactNumber(value int) string {
+	units := []string{"", "k", "M", "G", "T"}
+	v := float64(value)
+	i := 0
+	for v >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+
+	// 先按三位有效数字舍入，舍入到 1000 时进到下一个单位，例如 999999 显示为 1M 而不是 1000k
+	digits := 3 - len(strconv.Itoa(int(v)))
+	scale := math.Pow(10, float64(digits))
+	v = math.Round(v*scale) / scale
+	if v >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + units[i]
+}
+
+// 获取适合方块文字的字体：先按字符数选择字号并随方块大小缩放，放不下时继续缩小
+func tileFace(label string) font.Face {
+	return tileFaceFor(label, tileSize)
+}
+
+// 获取边长为 tile 的方块上文字的字体，用于缩小显示的棋盘
+func tileFaceFor(label string, tile int) font.Face {
+	size := tileFontSizes[len(tileFontSizes)-1]
+	if len(label) < len(tileFontSizes) {
+		size = tileFontSizes[len(label)]
+	}
+	size = math.Round(size * float64(tile) / defaultTileSize)
+
+	// 很小的方块按比例缩小留白
+	padding := float64(tileTextPadding)
+	if tile < 4*tileTextPadding {
+		padding = float64(tile) / 8
+	}
+	for {
+		face := tileFaceOfSize(size)
+		width, _ := textSize(face, label)
+		if width <= float64(tile)-2*padding || size <= tileFontMinSize {
+			return face
+		}
+		size -= 2
+	}
+}
+
+// 获取指定字号（逻辑单位）的方块字体
+func tileFaceOfSize(size float64) font.Face {
+	return fontFace(size * fontScale)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// 移动的危险程度
+const (
+	dangerNone    = iota
+	dangerTwoPly  // 随后的生成可能造成两步之内无路可走
+	dangerOnePly  // 随后的生成可能直接造成无路可走
+	dangerIllegal // 该方向不能移动
+)
+
+// 训练提示的颜色，alpha 未预乘
+var (
+	dangerColors = [...]color.NRGBA{
+		dangerNone:    {60, 170, 80, 220},
+		dangerTwoPly:  {240, 150, 40, 230},
+		dangerOnePly:  {220, 50, 47, 240},
+		dangerIllegal: {120, 120, 120, 110},
+	}
+	heatColor = color.NRGBA{220, 50, 47, 255}
+)
+
+// 训练提示的最大遮罩透明度
+const heatMaxAlpha = 140
+
+// 不能移动的方向提示的透明度
+const directionDisabledAlpha = 60
+
+// trainingInfo 一个局面的训练提示：各方向的危险程度和每格的拥堵程度
+type trainingInfo struct {
+	board  Board
+	danger [4]int
+	heat   [][]float64 // 0 到 1，越大越拥堵
+}
+
+// 计算局面的训练提示
+func analyzeTraining(b Board) *trainingInfo {
+	info := &trainingInfo{board: b.clone(), heat: boardHeat(b)}
+	for d := DirectionUp; d <= DirectionLeft; d++ {
+		after, _, moved := slideBoard(b, d)
+		if !moved {
+			info.danger[d] = dangerIllegal
+			continue
+		}
+		info.danger[d] = moveDanger(after)
+	}
+	return info
+}
+
+// 滑动后的危险程度：在所有可能的生成中，是否有一种直接或在下一步之后无路可走
+func moveDanger(after Board) int {
+	danger := dangerNone
+	forEachSpawn(after, func(spawned Board) bool {
+		if !spawned.canMove() {
+			danger = dangerOnePly
+			return false
+		}
+		if danger == dangerNone && forcedLoss(spawned) {
+			danger = dangerTwoPly
+		}
+		return true
+	})
+	return danger
+}
+
+// 下一步无论怎么走，都有一种生成会造成无路可走
+func forcedLoss(b Board) bool {
+	for d := DirectionUp; d <= DirectionLeft; d++ {
+		after, _, moved := slideBoard(b, d)
+		if !moved {
+			continue
+		}
+		dead := false
+		forEachSpawn(after, func(spawned Board) bool {
+			dead = !spawned.canMove()
+			return !dead
+		})
+		if !dead {
+			return false
+		}
+	}
+	return true
+}
+
+// 在每个空格上依次生成2和4并调用 visit，visit 返回 false 时停止；visit 期间棋盘被临时修改
+func forEachSpawn(b Board, visit func(spawned Board) bool) {
+	for r := range b {
+		for c := range b[r] {
+			if b[r][c] != 0 {
+				continue
+			}
+			for _, v := range []int{2, 4} {
+				b[r][c] = v
+				more := visit(b)
+				b[r][c] = 0
+				if !more {
+					return
+				}
+			}
+		}
+	}
+}
+
+// 每格的拥堵程度：方块四周可以合并或移入的格子越少越拥堵，再与相邻格子取平均表示区域
+func boardHeat(b Board) [][]float64 {
+	n := len(b)
+	stuck := make([][]float64, n)
+	for r := range b {
+		stuck[r] = make([]float64, n)
+		for c, v := range b[r] {
+			if v == 0 {
+				continue
+			}
+			free, total := 0, 0
+			for _, off := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				nr, nc := r+off[0], c+off[1]
+				if nr < 0 || nr >= n || nc < 0 || nc >= n {
+					continue
+				}
+				total++
+				if b[nr][nc] == 0 || b[nr][nc] == v {
+					free++
+				}
+			}
+			stuck[r][c] = 1 - float64(free)/float64(total)
+		}
+	}
+
+	heat := make([][]float64, n)
+	for r := range stuck {
+		heat[r] = make([]float64, n)
+		for c := range stuck[r] {
+			sum, count := 0.0, 0
+			for nr := r - 1; nr <= r+1; nr++ {
+				for nc := c - 1; nc <= c+1; nc++ {
+					if nr >= 0 && nr < n && nc >= 0 && nc < n {
+						sum += stuck[nr][nc]
+						count++
+					}
+				}
+			}
+			heat[r][c] = (stuck[r][c] + sum/float64(count)) / 2
+		}
+	}
+	return heat
+}
+
+// 切换训练提示并保存到配置
+func (g *Game) toggleTrainingOverlay() {
+	g.config.ShowTraining = !g.config.ShowTraining
+	g.saveConfig()
+	if g.config.ShowTraining {
+		g.showMessage(tr("msg.training_on"), 60)
+	} else {
+		g.showMessage(tr("msg.training_off"), 60)
+	}
+}
+
+// 当前局面的训练提示，棋盘变化时重新计算
+func (g *Game) trainingInfo() *trainingInfo {
+	if g.training == nil || !g.training.board.equal(g.board) {
+		g.training = analyzeTraining(g.board)
+	}
+	return g.training
+}
+
+// 绘制训练提示：每格的拥堵程度遮罩，以及棋盘四边各方向的危险程度
+func (g *Game) drawTrainingOverlay(screen *ebiten.Image) {
+	info := g.trainingInfo()
+	boardX, boardY := float64(layout.boardX), float64(layout.boardY)
+
+	for r, row := range info.heat {
+		for c, h := range row {
+			x := boardX + float64(c*(tileSize+tileMargin))
+			y := boardY + float64(r*(tileSize+tileMargin))
+			clr := heatColor
+			clr.A = uint8(h * heatMaxAlpha)
+			drawRect(screen, x, y, float64(tileSize), float64(tileSize), clr)
+		}
+	}
+
+	for d, pos := range directionMarkers() {
+		drawCircle(screen, pos[0], pos[1], float64(boardMargin)/2-1, dangerColors[info.danger[d]])
+		drawDirectionSymbol(screen, d, pos, color.White)
+	}
+}
+
+// 方向标记的位置，位于棋盘四边的留白中间
+func directionMarkers() [4][2]float64 {
+	boardX, boardY := float64(layout.boardX), float64(layout.boardY)
+	grid := float64(gridPixels())
+	inset := float64(boardMargin) / 2
+	return [4][2]float64{
+		DirectionUp:    {boardX + grid/2, boardY - inset},
+		DirectionRight: {boardX + grid + inset, boardY + grid/2},
+		DirectionDown:  {boardX + grid/2, boardY + grid + inset},
+		DirectionLeft:  {boardX - inset, boardY + grid/2},
+	}
+}
+
+// 在标记位置居中绘制方向符号
+func drawDirectionSymbol(screen *ebiten.Image, d int, pos [2]float64, clr color.Color) {
+	_, h := textSize(scoreFont, directionSymbols[d])
+	drawTextCentered(screen, directionSymbols[d], scoreFont, pos[0], pos[1]+h/2, clr)
+}
+
+// 绘制方向提示：可以移动的方向正常显示，不能移动的方向淡化
+func (g *Game) drawDirectionHints(screen *ebiten.Image) {
+	legal := g.legalMoves()
+	for d, pos := range directionMarkers() {
+		clr := color.NRGBA(currentTheme.Text)
+		if !legal[d] {
+			clr.A = directionDisabledAlpha
+		}
+		drawDirectionSymbol(screen, d, pos, clr)
+	}
+}